@@ -0,0 +1,206 @@
+package goutlinelib
+
+import(
+    "encoding/json"
+    "fmt"
+    "os"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// config key for the idle period (in seconds) after which a modified outline
+// is saved automatically; 0 disables autosaving
+const configAutosave = "autosave"
+
+const defaultAutosaveSeconds = 0
+
+// the recovery journal is written once there have been no modifications for
+// this long, so that typing is not slowed down by writing the whole outline
+const journalDelay = time.Second
+
+// sent after the idle period following a modification
+type autosaveMsg struct {
+    changeCount int
+}
+
+// sent after the (shorter) idle period for writing the recovery journal
+type journalMsg struct {
+    changeCount int
+}
+
+// sent when the recovery journal has been written in the background
+type journalWrittenMsg struct {
+    err error
+}
+
+// JournalFilename returns the name of the recovery journal kept next to the
+// file while there are unsaved changes.
+func JournalFilename(filename string) string {
+    return filename + ".journal"
+}
+
+func (m *model) MarkDirty() {
+    m.dirty = true
+    m.changeCount++
+}
+
+func (m *model) IsDirty() bool {
+    return m.dirty
+}
+
+// Save saves to the current file and, if that worked, marks the outline as
+// unmodified and drops the recovery journal; otherwise, the status tells why.
+func (m *model) Save() bool {
+    if 0 != m.newerVersion {
+        m.status = m.newerVersionStatus()
+        return false
+    }
+
+    if m.readOnly {
+        m.status = fmt.Sprintf("%s is opened read-only (press W to allow saving)", m.filename)
+        return false
+    }
+
+    if err := m.SaveCurrentAs(m.filename); nil != err {
+        m.status = fmt.Sprintf("could not save %s: %v", m.filename, err)
+        return false
+    }
+
+    m.dirty = false
+    os.Remove(JournalFilename(m.filename))
+
+    return true
+}
+
+func (m *model) autosaveCmd() tea.Cmd {
+    seconds := m.ConfigInt(configAutosave, defaultAutosaveSeconds)
+
+    if seconds <= 0 {
+        return nil
+    }
+
+    changeCount := m.changeCount
+
+    return tea.Tick(time.Duration(seconds) * time.Second, func(t time.Time) tea.Msg {
+        return autosaveMsg{changeCount: changeCount}
+    })
+}
+
+// afterModification schedules writing the recovery journal and an autosave for
+// the end of their idle periods.
+func (m *model) afterModification() tea.Cmd {
+    changeCount := m.changeCount
+
+    journal := tea.Tick(journalDelay, func(t time.Time) tea.Msg {
+        return journalMsg{changeCount: changeCount}
+    })
+
+    return tea.Batch(journal, m.autosaveCmd())
+}
+
+// handleJournal takes a snapshot of the outline and writes it to the recovery
+// journal in the background; the journal is always in goutline's own JSON
+// format, no matter which format the file itself uses.
+func (m *model) handleJournal(msg journalMsg) tea.Cmd {
+    // a later modification has scheduled another write
+    if msg.changeCount != m.changeCount || !m.dirty {
+        return nil
+    }
+
+    b, err := json.MarshalIndent(m, "", "    ")
+
+    if nil != err {
+        m.status = fmt.Sprintf("could not write recovery journal: %v", err)
+        return nil
+    }
+
+    journal := JournalFilename(m.filename)
+
+    return func() tea.Msg {
+        return journalWrittenMsg{err: WriteFileAtomic(journal, b, 0)}
+    }
+}
+
+func (m *model) handleJournalWritten(msg journalWrittenMsg) {
+    if nil != msg.err {
+        m.status = fmt.Sprintf("could not write recovery journal: %v", msg.err)
+    } else if !m.dirty {
+        // saved while the journal was written; it would be offered for
+        // recovery the next time otherwise
+        os.Remove(JournalFilename(m.filename))
+    }
+}
+
+func (m *model) handleAutosave(msg autosaveMsg) {
+    // a later modification has scheduled another autosave
+    if msg.changeCount != m.changeCount || !m.dirty || m.readOnly {
+        return
+    }
+
+    // otherwise, Save has told why
+    if m.Save() {
+        m.status = fmt.Sprintf("autosaved %s", m.filename)
+    }
+}
+
+// detectJournal remembers a recovery journal that is newer than the file, so
+// that the user can decide whether to recover it.
+func (m *model) detectJournal() {
+    journal, err := os.Stat(JournalFilename(m.filename))
+
+    if nil != err {
+        return
+    }
+
+    file, err := os.Stat(m.filename)
+
+    if nil == err && journal.ModTime().Before(file.ModTime()) {
+        return
+    }
+
+    m.pendingJournal = JournalFilename(m.filename)
+}
+
+func (m *model) RecoverJournal() error {
+    recovered, err := modelFromFileAs(m.pendingJournal, FileFormatJSON)
+
+    if nil != err {
+        return err
+    }
+
+    m.Title = recovered.Title
+    m.Config = recovered.Config
+    m.Cursor = recovered.Cursor
+    m.RebuildIdIndex()
+    m.UpdateLinearizedMapping()
+    m.MarkDirty()
+
+    return nil
+}
+
+func (m *model) handleJournalKey(msg tea.KeyMsg) {
+    switch msg.String() {
+
+    case "y":
+        if err := m.RecoverJournal(); nil != err {
+            m.status = fmt.Sprintf("could not recover %s: %v", m.pendingJournal, err)
+        } else {
+            m.status = fmt.Sprintf("recovered unsaved changes from %s", m.pendingJournal)
+        }
+
+        m.pendingJournal = ""
+
+    case "n":
+        os.Remove(m.pendingJournal)
+        m.pendingJournal = ""
+    }
+}
+
+func (m model) journalPromptView() string {
+    s := headerStyle.Render(fmt.Sprintf("%s [%s]", m.Title.GetTxt(), m.filename)) + "\n\n"
+    s += fmt.Sprintf("Found unsaved changes in %s, which is newer than %s.\n\n", m.pendingJournal, m.filename)
+    s += "Recover them? (y: recover, n: discard journal)\n"
+
+    return s
+}
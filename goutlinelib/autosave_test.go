@@ -0,0 +1,119 @@
+package goutlinelib
+
+import (
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestJournalRecovery(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "out.json"))
+
+    if !m.Save() || m.IsDirty() {
+        t.Fatal("Expected successful save to leave the model unmodified")
+    }
+
+    m.AddNewItem(m.Title).SetTxt("unsaved")
+
+    if !m.IsDirty() {
+        t.Error("Expected model to be modified after adding an item")
+    }
+
+    if nil != m.handleJournal(journalMsg{changeCount: m.changeCount - 1}) {
+        t.Error("Expected no journal to be written while changes are still happening")
+    }
+
+    if msg := m.handleJournal(journalMsg{changeCount: m.changeCount})().(journalWrittenMsg); msg.err != nil {
+        t.Fatal("Unexpected error", msg.err)
+    }
+
+    loaded, err := ModelFromFile(m.filename)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if loaded.pendingJournal != JournalFilename(m.filename) {
+        t.Fatal("Expected journal to be detected, but got", loaded.pendingJournal)
+    }
+
+    if err := loaded.RecoverJournal(); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if len(loaded.Title.GetSubs()) != 2 || loaded.Title.GetSubs()[1].GetTxt() != "unsaved" || !loaded.IsDirty() {
+        t.Error("Expected recovered and modified outline, but got", loaded.Title.GetSubs())
+    }
+
+    if !loaded.Save() {
+        t.Fatal("Expected successful save")
+    }
+
+    if _, err := os.Stat(JournalFilename(m.filename)); !os.IsNotExist(err) {
+        t.Error("Expected journal to be removed after saving")
+    }
+}
+
+func TestAutosaveOnlyAfterIdlePeriod(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "out.json"))
+
+    if nil != m.autosaveCmd() {
+        t.Error("Expected autosaving to be off by default")
+    }
+
+    m.SetConfigValue(configAutosave, "30")
+
+    m.AddNewItem(m.Title)
+    stale := autosaveMsg{changeCount: m.changeCount}
+    m.AddNewItem(m.Title)
+
+    m.handleAutosave(stale)
+
+    if _, err := os.Stat(m.filename); !os.IsNotExist(err) {
+        t.Error("Expected no autosave while changes are still happening")
+    }
+
+    m.handleAutosave(autosaveMsg{changeCount: m.changeCount})
+
+    if _, err := os.Stat(m.filename); err != nil || m.IsDirty() {
+        t.Error("Expected autosave after the idle period")
+    }
+}
+
+func TestQuitWithUnsavedChanges(t *testing.T) {
+    m := InitialModel()
+    m.SetFilename(filepath.Join(os.TempDir(), "goutline-does-not-exist", "out.json"))
+
+    if nil == m.RequestQuit() {
+        t.Error("Expected unmodified model to quit right away")
+    }
+
+    m.AddNewItem(m.Title)
+
+    if nil != m.RequestQuit() || !m.confirmingQuit {
+        t.Error("Expected confirmation prompt for modified model")
+    }
+
+    if m.Save() || !m.IsDirty() {
+        t.Error("Expected failed save to keep the model modified")
+    }
+
+    if !strings.HasPrefix(m.status, "could not save") || !strings.Contains(m.status, "no such file") {
+        t.Error("Expected failed save to tell why, but got", m.status)
+    }
+}
@@ -0,0 +1,80 @@
+package goutlinelib
+
+import(
+    "fmt"
+)
+
+// referenceTarget returns the id of the item that item refers to, if it is a
+// transclusion or link.
+func referenceTarget(item OItem) string {
+    switch ref := item.(type) {
+
+    case *oitemproxy:
+        // empty for the subs of a transclusion, which are not stored
+        return ref.Target
+
+    case *oitemlink:
+        return ref.Target
+    }
+
+    return ""
+}
+
+func (m *model) addBacklink(item OItem) {
+    if target := referenceTarget(item); "" != target {
+        m.backlinks[target] = append(m.backlinks[target], item)
+    }
+}
+
+func (m *model) removeBacklink(item OItem) {
+    target := referenceTarget(item)
+
+    if "" == target {
+        return
+    }
+
+    referrers := m.backlinks[target]
+
+    for i, cur := range referrers {
+        if cur == item {
+            referrers = append(referrers[:i], referrers[i + 1:]...)
+            break
+        }
+    }
+
+    if 0 == len(referrers) {
+        delete(m.backlinks, target)
+    } else {
+        m.backlinks[target] = referrers
+    }
+}
+
+// Backlinks returns the transclusions of and links to item.
+func (m *model) Backlinks(item OItem) []OItem {
+    // a transclusion shows the referrers of its target
+    if proxy, ok := item.(*oitemproxy); ok {
+        item = proxy.target
+    }
+
+    if "" == item.GetId() {
+        return nil
+    }
+
+    return m.backlinks[item.GetId()]
+}
+
+func (m *model) OpenBacklinkList(item OItem) {
+    describe := func(referrer OItem) string {
+        kind := "transclusion"
+
+        if OTypeProxyLink == referrer.GetType() {
+            kind = "link"
+        }
+
+        return fmt.Sprintf("%s  %s", kind, m.itemLocation(referrer))
+    }
+
+    referrers := append([]OItem(nil), m.Backlinks(item)...)
+
+    m.OpenItemList(fmt.Sprintf("Items referring to %q", item.GetTxt()), referrers, describe, "no items refer to this item")
+}
@@ -0,0 +1,66 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestBacklinksFollowStructuralChanges(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    other := m.AddNewItem(m.Title)
+
+    proxy := NewProxy(target)
+    m.AddSubAfterThis(other, proxy)
+    link := NewLink(target)
+    m.AddSubAfterThis(other, link)
+
+    if referrers := m.Backlinks(target); len(referrers) != 2 {
+        t.Fatal("Expected transclusion and link as backlinks, but got", referrers)
+    }
+
+    if referrers := m.Backlinks(proxy); len(referrers) != 2 {
+        t.Error("Expected transclusion to show the backlinks of its target, but got", referrers)
+    }
+
+    m.PushUndo()
+    cut := m.DeleteItem(link)
+
+    if referrers := m.Backlinks(target); len(referrers) != 1 || referrers[0] != proxy {
+        t.Error("Expected only the transclusion after deleting the link, but got", referrers)
+    }
+
+    m.AddSubAfterThis(other, cut)
+
+    if referrers := m.Backlinks(target); len(referrers) != 2 {
+        t.Error("Expected the link to count again after pasting it, but got", referrers)
+    }
+
+    m.PopUndo()
+
+    if referrers := m.Backlinks(m.Title.GetSubs()[0]); len(referrers) != 2 {
+        t.Error("Expected backlinks to be rebuilt after undo, but got", referrers)
+    }
+}
+
+func TestJumpToBacklink(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    other := m.AddNewItem(m.Title)
+    link := NewLink(target)
+    m.AddSubAfterThis(other, link)
+    m.Cursor = m.PosInLinearized(target)
+
+    m.OpenBacklinkList(target)
+
+    if nil == m.itemList || len(m.itemList.items) != 1 {
+        t.Fatal("Expected list with one referrer")
+    }
+
+    if !m.jumpTo(m.itemList.items[0]) || m.linearized[m.Cursor] != link {
+        t.Error("Expected cursor on the link")
+    }
+
+    if !m.JumpBack() || m.linearized[m.Cursor] != target {
+        t.Error("Expected to jump back to the target")
+    }
+}
@@ -0,0 +1,269 @@
+package goutlinelib
+
+import(
+    "errors"
+    "fmt"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// config key for the number of backup generations kept when saving
+const configBackups = "backups"
+
+const defaultBackupGenerations = 3
+
+type Backup struct {
+    Generation int
+    Filename string
+    ModTime time.Time
+}
+
+// BackupFilename returns the name of the given backup generation, where 1 is
+// the most recent one ($filename.bak, then $filename.bak.2, ...).
+func BackupFilename(filename string, generation int) string {
+    if 1 == generation {
+        return filename + ".bak"
+    }
+
+    return fmt.Sprintf("%s.bak.%d", filename, generation)
+}
+
+// ListBackups returns the existing backup generations of a file, newest first.
+func ListBackups(filename string) []Backup {
+    var result []Backup
+
+    for generation := 1; ; generation++ {
+        backup_filename := BackupFilename(filename, generation)
+        info, err := os.Stat(backup_filename)
+
+        if err != nil {
+            break
+        }
+
+        result = append(result, Backup{Generation: generation, Filename: backup_filename, ModTime: info.ModTime()})
+    }
+
+    return result
+}
+
+func copyFile(from string, to string) (err error) {
+    in, err := os.Open(from)
+
+    if err != nil {
+        return
+    }
+
+    defer in.Close()
+
+    out, err := os.Create(to)
+
+    if err != nil {
+        return
+    }
+
+    _, err = io.Copy(out, in)
+
+    if nil == err {
+        err = out.Sync()
+    }
+
+    if closeErr := out.Close(); nil == err {
+        err = closeErr
+    }
+
+    return
+}
+
+// rotateBackups shifts the existing backups by one generation (dropping the
+// oldest one) and makes the current contents of the file the newest backup.
+func rotateBackups(filename string, generations int) error {
+    if generations <= 0 {
+        return nil
+    }
+
+    if _, err := os.Stat(filename); os.IsNotExist(err) {
+        return nil
+    }
+
+    os.Remove(BackupFilename(filename, generations))
+
+    for generation := generations - 1; generation >= 1; generation-- {
+        from := BackupFilename(filename, generation)
+
+        if _, err := os.Stat(from); err == nil {
+            if err = os.Rename(from, BackupFilename(filename, generation + 1)); err != nil {
+                return fmt.Errorf("Could not rotate backup %s: %w", from, err)
+            }
+        }
+    }
+
+    newest := BackupFilename(filename, 1)
+
+    // the original stays in place until it is replaced by the new contents
+    if err := os.Link(filename, newest); err != nil {
+        if err = copyFile(filename, newest); err != nil {
+            return fmt.Errorf("Could not create backup %s: %w", newest, err)
+        }
+    }
+
+    return nil
+}
+
+func syncDir(dir string) {
+    // not supported on all platforms, so this is best effort only
+    if d, err := os.Open(dir); err == nil {
+        d.Sync()
+        d.Close()
+    }
+}
+
+// WriteFileAtomic writes to a temporary file in the same directory and renames
+// it over the target, so that the target is never left half written. Before
+// replacing the target, it is kept as the newest of the given number of
+// backup generations.
+func WriteFileAtomic(filename string, data []byte, backupGenerations int) (err error) {
+    dir := filepath.Dir(filename)
+
+    tmp, err := ioutil.TempFile(dir, "." + filepath.Base(filename) + ".tmp")
+
+    if err != nil {
+        return fmt.Errorf("Could not create temporary file for %s: %w", filename, err)
+    }
+
+    defer func() {
+        if nil != err {
+            tmp.Close()
+            os.Remove(tmp.Name())
+        }
+    }()
+
+    var mode os.FileMode = 0644
+
+    if info, statErr := os.Stat(filename); statErr == nil {
+        mode = info.Mode().Perm()
+    }
+
+    if _, err = tmp.Write(data); err != nil {
+        return fmt.Errorf("Could not write %s: %w", tmp.Name(), err)
+    }
+
+    if err = tmp.Sync(); err != nil {
+        return fmt.Errorf("Could not sync %s: %w", tmp.Name(), err)
+    }
+
+    if err = tmp.Close(); err != nil {
+        return fmt.Errorf("Could not close %s: %w", tmp.Name(), err)
+    }
+
+    if err = os.Chmod(tmp.Name(), mode); err != nil {
+        return fmt.Errorf("Could not set permissions of %s: %w", tmp.Name(), err)
+    }
+
+    if err = rotateBackups(filename, backupGenerations); err != nil {
+        return
+    }
+
+    if err = os.Rename(tmp.Name(), filename); err != nil {
+        return fmt.Errorf("Could not replace %s: %w", filename, err)
+    }
+
+    syncDir(dir)
+
+    return nil
+}
+
+func (m *model) BackupGenerations() int {
+    return m.ConfigInt(configBackups, defaultBackupGenerations)
+}
+
+// RestoreBackup replaces the outline with a backup generation; this can be
+// undone, and the file itself is only changed when saving. As with
+// ModelFromFile, a *LoadError means that the backup was restored, but had to
+// be repaired.
+func (m *model) RestoreBackup(backup Backup) error {
+    restored, err := modelFromFileAs(backup.Filename, FormatForFilename(m.filename))
+
+    var loadErr *LoadError
+
+    if nil != err && !errors.As(err, &loadErr) {
+        return err
+    }
+
+    if restored.IsNewerVersion() {
+        return fmt.Errorf("written by a newer version of goutline (file format %d)", restored.newerVersion)
+    }
+
+    m.PushUndo()
+
+    m.Title = restored.Title
+    m.Config = restored.Config
+    m.Cursor = restored.Cursor
+    m.RebuildIdIndex()
+    m.UpdateLinearizedMapping()
+    m.MarkDirty()
+
+    return err
+}
+
+func (m *model) OpenBackupList() {
+    m.backups = ListBackups(m.filename)
+    m.backupCursor = 0
+
+    if 0 == len(m.backups) {
+        m.status = fmt.Sprintf("no backups of %s", m.filename)
+    } else {
+        m.choosingBackup = true
+    }
+}
+
+func (m *model) handleBackupListKey(msg tea.KeyMsg) {
+    switch msg.String() {
+
+    case "up", "k":
+        if m.backupCursor > 0 {
+            m.backupCursor--
+        }
+
+    case "down", "j":
+        if m.backupCursor < len(m.backups) - 1 {
+            m.backupCursor++
+        }
+
+    case "enter":
+        backup := m.backups[m.backupCursor]
+
+        var loadErr *LoadError
+
+        if err := m.RestoreBackup(backup); errors.As(err, &loadErr) {
+            m.status = fmt.Sprintf("restored %s, repairing %d problems (u to undo, s to save)", backup.Filename, len(loadErr.Problems))
+        } else if nil != err {
+            m.status = fmt.Sprintf("could not restore %s: %v", backup.Filename, err)
+        } else {
+            m.status = fmt.Sprintf("restored %s (u to undo, s to save)", backup.Filename)
+        }
+
+        m.choosingBackup = false
+
+    case "esc", "q", "ctrl+c":
+        m.choosingBackup = false
+    }
+}
+
+func (m model) backupListView() string {
+    s := headerStyle.Render(fmt.Sprintf("Backups of %s", m.filename)) + "\n\n"
+
+    for i, backup := range m.backups {
+        line := fmt.Sprintf("%d  %s  %s", backup.Generation, backup.ModTime.Format("2006-01-02 15:04:05"), filepath.Base(backup.Filename))
+
+        s += listLine(line, i == m.backupCursor)
+    }
+
+    s += "\nenter: restore   esc: cancel\n"
+
+    return s
+}
@@ -0,0 +1,86 @@
+package goutlinelib
+
+import (
+    "errors"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func readString(t *testing.T, filename string) string {
+    b, err := ioutil.ReadFile(filename)
+    if err != nil {
+        t.Fatal("Could not read", filename, err)
+    }
+
+    return string(b)
+}
+
+func TestWriteFileAtomicRotatesBackups(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    filename := filepath.Join(dir, "out.json")
+
+    for _, contents := range []string{"1", "2", "3", "4"} {
+        if err := WriteFileAtomic(filename, []byte(contents), 2); err != nil {
+            t.Fatal("Unexpected error", err)
+        }
+    }
+
+    if res := readString(t, filename); res != "4" {
+        t.Error("Expected", "4", "in file, but got", res)
+    }
+
+    backups := ListBackups(filename)
+
+    if len(backups) != 2 {
+        t.Fatal("Expected", 2, "backups, but got", len(backups))
+    }
+
+    if res := readString(t, backups[0].Filename); res != "3" {
+        t.Error("Expected", "3", "in newest backup, but got", res)
+    }
+
+    if res := readString(t, backups[1].Filename); res != "2" {
+        t.Error("Expected", "2", "in oldest backup, but got", res)
+    }
+
+    entries, _ := ioutil.ReadDir(dir)
+
+    if len(entries) != 3 {
+        t.Error("Expected no leftover temporary files, but got", len(entries), "entries")
+    }
+}
+
+func TestRestoreRepairedBackup(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "out.json"))
+
+    backup := Backup{Generation: 1, Filename: BackupFilename(m.filename, 1)}
+    damaged := `{"Title": {"Txt": "t", "Subs": [{"Txt": "from backup", "Checked": "yes"}]}}`
+
+    if err := ioutil.WriteFile(backup.Filename, []byte(damaged), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    var loadErr *LoadError
+
+    if err := m.RestoreBackup(backup); !errors.As(err, &loadErr) {
+        t.Fatal("Expected problems of the repaired backup, but got", err)
+    }
+
+    if subs := m.Title.GetSubs(); len(subs) != 1 || subs[0].GetTxt() != "from backup" || !m.IsDirty() {
+        t.Error("Expected repaired backup to be restored, but got", subs)
+    }
+}
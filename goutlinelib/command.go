@@ -0,0 +1,196 @@
+package goutlinelib
+
+import(
+    "bytes"
+    "fmt"
+    "os/exec"
+    "runtime"
+    "strings"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// meta properties of command items: seconds between runs (0 or missing: run
+// only when expanded or refreshed), and seconds after which a run is aborted
+const metaCommandRefresh = "refresh"
+const metaCommandTimeout = "timeout"
+
+const defaultCommandTimeoutSeconds = 10
+
+// sent when a command has finished (or timed out)
+type commandOutputMsg struct {
+    id string
+    seq int
+    lines []string
+    err error
+}
+
+// sent when the output of a command with a refresh interval is due again
+type commandRefreshMsg struct {
+    id string
+    seq int
+}
+
+// NewCommand creates an external item whose subs are the lines a shell
+// command writes to stdout.
+func NewCommand(command string) OItem {
+    result := &oitemexternal{Id: NewId(), Source: command, Kind: externalKindCommand}
+    result.Init()
+    result.SetTimestampCreatedNow()
+
+    return result
+}
+
+// shellCommand returns the command that runs command in the shell of the
+// platform.
+func shellCommand(command string) *exec.Cmd {
+    if "windows" == runtime.GOOS {
+        return exec.Command("cmd", "/C", command)
+    }
+
+    return exec.Command("sh", "-c", command)
+}
+
+func runCommand(id string, seq int, command string, dir string, timeout time.Duration) tea.Cmd {
+    return func() tea.Msg {
+        msg := commandOutputMsg{id: id, seq: seq}
+
+        var stdout, stderr bytes.Buffer
+
+        cmd := shellCommand(command)
+        cmd.Dir = dir
+        cmd.Stdout = &stdout
+        cmd.Stderr = &stderr
+
+        if err := cmd.Start(); nil != err {
+            msg.err = err
+            return msg
+        }
+
+        done := make(chan error, 1)
+
+        go func() {
+            done <- cmd.Wait()
+        }()
+
+        select {
+
+        case err := <-done:
+            if txt := strings.TrimRight(stdout.String(), "\n"); "" != txt {
+                msg.lines = strings.Split(txt, "\n")
+            }
+
+            if nil != err {
+                if errTxt := strings.TrimSpace(stderr.String()); "" != errTxt {
+                    err = fmt.Errorf("%v: %s", err, strings.SplitN(errTxt, "\n", 2)[0])
+                }
+
+                msg.err = err
+            }
+
+        case <-time.After(timeout):
+            cmd.Process.Kill()
+            msg.err = fmt.Errorf("timed out after %v", timeout)
+        }
+
+        return msg
+    }
+}
+
+// startCommands runs the commands of all visible, expanded command items
+// whose output is missing or outdated. Command items are only expanded by the
+// user in the current session, never by loading a file.
+func (m *model) startCommands() []tea.Cmd {
+    var cmds []tea.Cmd
+
+    for _, item := range m.linearized {
+        external, ok := item.(*oitemexternal)
+
+        if !ok || externalKindCommand != external.Kind || !external.IsExpanded() || external.loaded || external.running {
+            continue
+        }
+
+        // never run commands from mounted outlines
+        if m.IsGenerated(external) {
+            continue
+        }
+
+        external.running = true
+        external.runSeq++
+
+        timeout := time.Duration(MetaInt(external, metaCommandTimeout, defaultCommandTimeoutSeconds)) * time.Second
+        cmds = append(cmds, runCommand(external.Id, external.runSeq, external.Source, external.base, timeout))
+    }
+
+    return cmds
+}
+
+// commandOf returns the command item a message is meant for, unless the
+// message is outdated.
+func (m *model) commandOf(id string, seq int) *oitemexternal {
+    external, ok := m.ItemById(id).(*oitemexternal)
+
+    if !ok || seq != external.runSeq {
+        return nil
+    }
+
+    return external
+}
+
+func (m *model) handleCommandOutput(msg commandOutputMsg) tea.Cmd {
+    external := m.commandOf(msg.id, msg.seq)
+
+    if nil == external || !external.running {
+        return nil
+    }
+
+    subs := make([]OItem, 0, len(msg.lines) + 1)
+
+    for _, line := range msg.lines {
+        subs = append(subs, &oitem{Type: "oitem", Txt: line, parent: external})
+    }
+
+    if nil != msg.err {
+        subs = append(subs, &oitem{Type: "oitem", Txt: fmt.Sprintf("⚠ %v", msg.err), parent: external})
+    }
+
+    external.subs = subs
+    external.loaded = true
+    external.running = false
+
+    // keep the cursor on the same item, even if the output above it changed
+    cur := m.linearized[m.Cursor]
+    m.UpdateLinearizedMapping()
+
+    if pos := m.PosInLinearized(cur); -1 != pos {
+        m.Cursor = pos
+    } else if pos := m.PosInLinearized(external); -1 != pos {
+        m.Cursor = pos
+    }
+
+    seconds := MetaInt(external, metaCommandRefresh, 0)
+
+    if seconds <= 0 {
+        return nil
+    }
+
+    id := msg.id
+    seq := msg.seq
+
+    return tea.Tick(time.Duration(seconds) * time.Second, func(t time.Time) tea.Msg {
+        return commandRefreshMsg{id: id, seq: seq}
+    })
+}
+
+func (m *model) handleCommandRefresh(msg commandRefreshMsg) {
+    external := m.commandOf(msg.id, msg.seq)
+
+    if nil == external {
+        return
+    }
+
+    // the previous output stays visible until the new one arrives; collapsed
+    // items are run again when expanded
+    external.loaded = false
+}
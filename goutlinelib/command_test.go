@@ -0,0 +1,96 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+)
+
+func runCommandItem(t *testing.T, m *model, item OItem) commandOutputMsg {
+    m.Expand(item)
+    cmds := m.startCommands()
+
+    if len(cmds) != 1 {
+        t.Fatal("Expected one command to be started, but got", len(cmds))
+    }
+
+    if len(m.startCommands()) != 0 {
+        t.Error("Expected running command not to be started again")
+    }
+
+    return cmds[0]().(commandOutputMsg)
+}
+
+func TestCommandOutput(t *testing.T) {
+    m := InitialModel()
+    item := NewCommand("printf 'a\\nb\\n'")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], item)
+
+    msg := runCommandItem(t, &m, item)
+
+    if subs := item.GetSubs(); len(subs) != 1 || !strings.Contains(subs[0].GetTxt(), "running") {
+        t.Error("Expected placeholder while the command is running, but got", subs)
+    }
+
+    m.handleCommandOutput(msg)
+
+    if subs := item.GetSubs(); len(subs) != 2 || subs[0].GetTxt() != "a" || subs[1].GetTxt() != "b" {
+        t.Error("Expected output lines as subs, but got", subs)
+    }
+
+    // outdated output is ignored
+    m.RefreshExternal(item)
+    m.handleCommandOutput(msg)
+
+    if subs := item.GetSubs(); len(subs) != 1 || !strings.Contains(subs[0].GetTxt(), "running") {
+        t.Error("Expected outdated output to be ignored, but got", subs)
+    }
+}
+
+func TestCommandErrors(t *testing.T) {
+    m := InitialModel()
+    failing := NewCommand("echo partial; echo broken >&2; exit 3")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], failing)
+
+    m.handleCommandOutput(runCommandItem(t, &m, failing))
+
+    if subs := failing.GetSubs(); len(subs) != 2 || subs[0].GetTxt() != "partial" || !strings.Contains(subs[1].GetTxt(), "exit status 3: broken") {
+        t.Error("Expected output and error as subs, but got", subs)
+    }
+
+    slow := NewCommand("sleep 5")
+    SetMetaValue(slow, metaCommandTimeout, "1")
+    m.AddSubAfterThis(failing, slow)
+
+    m.handleCommandOutput(runCommandItem(t, &m, slow))
+
+    if subs := slow.GetSubs(); len(subs) != 1 || !strings.Contains(subs[0].GetTxt(), "timed out") {
+        t.Error("Expected timeout as sub, but got", subs)
+    }
+}
+
+func TestCommandsDoNotRunWhenLoaded(t *testing.T) {
+    m := InitialModel()
+    item := NewCommand("echo ran")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], item)
+    m.Expand(item)
+
+    b, err := json.Marshal(item)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if !item.IsExpanded() || strings.Contains(string(b), `"Expanded":true`) {
+        t.Error("Expected command item to be expanded, but saved collapsed, got", string(b))
+    }
+
+    // files written before command items were saved collapsed
+    loaded := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Type": "oitemexternal", "Source": "echo ran", "Kind": "command", "Expanded": true}]}}`))
+    loaded.UpdateLinearizedMapping()
+
+    if cmds := loaded.startCommands(); len(cmds) != 0 {
+        t.Error("Expected no command to run after loading, but got", len(cmds))
+    }
+
+    runCommandItem(t, &loaded, loaded.Title.GetSubs()[0])
+}
@@ -0,0 +1,105 @@
+package goutlinelib
+
+import(
+    "fmt"
+)
+
+// config key for how checked items are shown: completedShow, completedDim or
+// completedHide (checked items and their subs are not shown at all)
+const configCompleted = "completed"
+
+const (
+    completedShow = "show"
+    completedDim = "dim"
+    completedHide = "hide"
+)
+
+func (m *model) CompletedMode() string {
+    switch value, _ := m.ConfigValue(configCompleted); value {
+
+    case completedDim, completedHide:
+        return value
+    }
+
+    return completedShow
+}
+
+// CycleCompletedMode switches between showing, dimming and hiding checked
+// items; the mode is saved with the file.
+func (m *model) CycleCompletedMode() {
+    next := map[string]string{
+        completedShow: completedDim,
+        completedDim: completedHide,
+        completedHide: completedShow,
+    }[m.CompletedMode()]
+
+    m.SetConfigValue(configCompleted, next)
+    m.MarkDirty()
+
+    m.updateLinearizedKeepingCursor()
+    m.status = fmt.Sprintf("checked items: %s", next)
+}
+
+// updateLinearizedKeepingCursor updates the linearization; if the item under
+// the cursor is not shown anymore, the cursor moves to the next item that is
+// still shown (or the previous one, if there is none).
+func (m *model) updateLinearizedKeepingCursor() {
+    before := m.linearized
+    cursor := m.Cursor
+
+    m.UpdateLinearizedMapping()
+
+    if cursor < 0 || cursor >= len(before) {
+        return
+    }
+
+    for i := cursor; i < len(before); i++ {
+        if pos := m.PosInLinearized(before[i]); -1 != pos {
+            m.Cursor = pos
+            return
+        }
+    }
+
+    for i := cursor - 1; i >= 0; i-- {
+        if pos := m.PosInLinearized(before[i]); -1 != pos {
+            m.Cursor = pos
+            return
+        }
+    }
+
+    m.Cursor = 0
+}
+
+// HiddenCompletedCount returns the number of items not shown because they
+// are (or are below) checked items.
+func (m *model) HiddenCompletedCount() int {
+    if completedHide != m.CompletedMode() {
+        return 0
+    }
+
+    count := 0
+
+    var walk func(item OItem, hidden bool)
+
+    walk = func(item OItem, hidden bool) {
+        hidden = hidden || item.IsChecked()
+
+        if hidden {
+            count++
+        }
+
+        if !storesSubs(item) {
+            return
+        }
+
+        for _, sub := range item.GetSubs() {
+            walk(sub, hidden)
+        }
+    }
+
+    for _, sub := range m.Title.GetSubs() {
+        walk(sub, false)
+    }
+
+    return count
+}
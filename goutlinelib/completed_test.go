@@ -0,0 +1,59 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestHideCompletedItems(t *testing.T) {
+    m := InitialModel()
+    first := m.Title.GetSubs()[0]
+    second := m.AddNewItem(m.Title)
+    m.AddNewItem(second)
+    m.Expand(second)
+    third := m.AddNewItem(m.Title)
+
+    if m.CompletedMode() != completedShow {
+        t.Fatal("Expected checked items to be shown by default")
+    }
+
+    m.CycleCompletedMode()
+    m.CycleCompletedMode()
+
+    if mode, _ := m.ConfigValue(configCompleted); mode != completedHide {
+        t.Fatal("Expected mode to be stored in the config, but got", mode)
+    }
+
+    m.Cursor = m.PosInLinearized(second)
+    m.ToggleChecked(second)
+
+    if -1 != m.PosInLinearized(second) || len(m.linearized) != 2 {
+        t.Error("Expected checked item and its subs to be hidden, but got", m.linearized)
+    }
+
+    if m.linearized[m.Cursor] != third {
+        t.Error("Expected cursor to move to the next shown item")
+    }
+
+    if count := m.HiddenCompletedCount(); count != 2 {
+        t.Error("Expected 2 hidden items, but got", count)
+    }
+
+    m.Cursor = m.PosInLinearized(third)
+    m.ToggleChecked(third)
+
+    if m.linearized[m.Cursor] != first {
+        t.Error("Expected cursor to move to the previous item if there is no next one")
+    }
+
+    m.ToggleChecked(first)
+
+    if len(m.linearized) == 0 {
+        t.Error("Expected items to be shown when all are checked")
+    }
+
+    m.CycleCompletedMode()
+
+    if m.CompletedMode() != completedShow || len(m.linearized) != 4 {
+        t.Error("Expected all items to be shown again, but got", m.linearized)
+    }
+}
@@ -0,0 +1,23 @@
+package goutlinelib
+
+// Per-file settings are stored as meta properties of the config item.
+
+func (m *model) ConfigValue(key string) (string, bool) {
+    return MetaValue(m.Config, key)
+}
+
+func (m *model) ConfigInt(key string, fallback int) int {
+    return MetaInt(m.Config, key, fallback)
+}
+
+func (m *model) ConfigBool(key string, fallback bool) bool {
+    return MetaBool(m.Config, key, fallback)
+}
+
+func (m *model) SetConfigValue(key string, value string) {
+    if nil == m.Config {
+        m.Config = &oitem{Type: "oitem"}
+    }
+
+    SetMetaValue(m.Config, key, value)
+}
@@ -0,0 +1,330 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/charmbracelet/lipgloss"
+)
+
+// meta properties with the dates of an item
+const (
+    metaDue = "due"
+    metaScheduled = "scheduled"
+)
+
+const agendaDayLayout = "Mon 2006-01-02"
+
+func startOfDay(t time.Time) time.Time {
+    t = t.Local()
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// addDateOffset adds count days, weeks, months or years (unit d, w, m or y).
+func addDateOffset(day time.Time, count int, unit string) (time.Time, bool) {
+    switch unit {
+
+    case "d", "day", "days":
+        return day.AddDate(0, 0, count), true
+
+    case "w", "week", "weeks":
+        return day.AddDate(0, 0, 7 * count), true
+
+    case "m", "month", "months":
+        return day.AddDate(0, count, 0), true
+
+    case "y", "year", "years":
+        return day.AddDate(count, 0, 0), true
+    }
+
+    return day, false
+}
+
+// parseDateOffset parses e.g. "+3d", "-1w", "2 weeks" or "in 3 days".
+func parseDateOffset(value string, today time.Time) (time.Time, bool) {
+    value = strings.TrimSpace(strings.TrimPrefix(value, "in "))
+
+    sign := 1
+
+    if strings.HasPrefix(value, "+") {
+        value = value[1:]
+    } else if strings.HasPrefix(value, "-") {
+        sign = -1
+        value = value[1:]
+    }
+
+    digits := 0
+
+    for digits < len(value) && value[digits] >= '0' && value[digits] <= '9' {
+        digits++
+    }
+
+    if 0 == digits {
+        return today, false
+    }
+
+    count, err := strconv.Atoi(value[:digits])
+
+    if nil != err {
+        return today, false
+    }
+
+    return addDateOffset(today, sign * count, strings.TrimSpace(value[digits:]))
+}
+
+// parseWeekday returns the next given weekday after today, e.g. for "fri" or
+// "next friday".
+func parseWeekday(value string, today time.Time) (time.Time, bool) {
+    value = strings.TrimPrefix(value, "next ")
+
+    if len(value) < 3 {
+        return today, false
+    }
+
+    for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+        name := strings.ToLower(weekday.String())
+
+        if strings.HasPrefix(name, value) {
+            days := (int(weekday) - int(today.Weekday()) + 7) % 7
+
+            if 0 == days {
+                days = 7
+            }
+
+            return today.AddDate(0, 0, days), true
+        }
+    }
+
+    return today, false
+}
+
+// ParseDate understands "today", "tomorrow", "yesterday", weekdays ("fri",
+// "next monday"), offsets from today ("+3d", "-1w", "in 2 months", "next
+// week") and dates as YYYY-MM-DD, optionally with HH:MM.
+func ParseDate(value string, now time.Time) (time.Time, error) {
+    today := startOfDay(now)
+    lower := strings.ToLower(strings.Join(strings.Fields(value), " "))
+
+    switch lower {
+
+    case "today", "tod":
+        return today, nil
+
+    case "tomorrow", "tom":
+        return today.AddDate(0, 0, 1), nil
+
+    case "yesterday":
+        return today.AddDate(0, 0, -1), nil
+    }
+
+    if strings.HasPrefix(lower, "next ") {
+        if result, ok := addDateOffset(today, 1, lower[len("next "):]); ok {
+            return result, nil
+        }
+    }
+
+    if result, ok := parseDateOffset(lower, today); ok {
+        return result, nil
+    }
+
+    if result, ok := parseWeekday(lower, today); ok {
+        return result, nil
+    }
+
+    if result, ok := parseMetaDate(strings.TrimSpace(value)); ok {
+        return result, nil
+    }
+
+    return today, fmt.Errorf("unknown date %q", value)
+}
+
+// SetDate asks for the date stored in the property key (metaDue or
+// metaScheduled) of item; an empty date removes it. The change can be undone.
+func (m *model) SetDate(item OItem, key string) {
+    if !canHaveMeta(item) {
+        m.status = "links have no dates"
+        return
+    }
+
+    value, _ := MetaValue(item, key)
+
+    m.OpenPrompt(key + ": ", value, func(m *model, value string) {
+        var date time.Time
+
+        if "" != strings.TrimSpace(value) {
+            var err error
+
+            if date, err = ParseDate(value, time.Now()); nil != err {
+                m.status = err.Error()
+                return
+            }
+        }
+
+        m.PushUndo()
+
+        if date.IsZero() {
+            DeleteMetaValue(item, key)
+        } else {
+            SetMetaDate(item, key, date)
+            m.status = fmt.Sprintf("%s: %s", key, date.Format(agendaDayLayout))
+        }
+
+        item.SetTimestampChangedNow()
+        m.MarkDirty()
+
+        // e.g. a filter by date
+        m.UpdateLinearizedMapping()
+    })
+}
+
+// IsOverdue tells whether item is not checked, but was due before today.
+func IsOverdue(item OItem, now time.Time) bool {
+    due, ok := MetaDateValue(item, metaDue)
+
+    return ok && !item.IsChecked() && due.Before(startOfDay(now))
+}
+
+// datesView returns the dates of item as shown next to its text; overdue
+// items are highlighted, items due today as well.
+func datesView(item OItem, now time.Time) string {
+    s := ""
+    gray := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+    if scheduled, ok := MetaValue(item, metaScheduled); ok {
+        s += gray.Render(" ⏵" + scheduled)
+    }
+
+    due, ok := MetaDateValue(item, metaDue)
+
+    if !ok {
+        return s
+    }
+
+    value, _ := MetaValue(item, metaDue)
+    style := gray
+
+    if IsOverdue(item, now) {
+        style = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
+    } else if !item.IsChecked() && startOfDay(due).Equal(startOfDay(now)) {
+        style = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+    }
+
+    return s + style.Render(" ⚑" + value)
+}
+
+type agendaEntry struct {
+    day time.Time
+    key string
+    item OItem
+}
+
+// shown instead of the outline: the unchecked items with dates, by day
+type agenda struct {
+    entries []agendaEntry
+    cursor int
+}
+
+// agendaEntries returns an entry for every date of the unchecked items,
+// ordered by day (and within a day, by outline order).
+func (m *model) agendaEntries() []agendaEntry {
+    var result []agendaEntry
+
+    walkStored(m.Title, func(item OItem) {
+        // transclusions would list their target twice
+        if _, ok := item.(*oitemproxy); ok || item == m.Title || item.IsChecked() {
+            return
+        }
+
+        for _, key := range []string{metaScheduled, metaDue} {
+            if date, ok := MetaDateValue(item, key); ok {
+                result = append(result, agendaEntry{day: startOfDay(date), key: key, item: item})
+            }
+        }
+    })
+
+    sort.SliceStable(result, func(i, j int) bool {
+        return result[i].day.Before(result[j].day)
+    })
+
+    return result
+}
+
+func (m *model) OpenAgenda() {
+    entries := m.agendaEntries()
+
+    if 0 == len(entries) {
+        m.status = "no unchecked items with dates"
+        return
+    }
+
+    m.agenda = &agenda{entries: entries}
+}
+
+func (m *model) handleAgendaKey(msg tea.KeyMsg) {
+    a := m.agenda
+
+    switch msg.String() {
+
+    case "up", "k":
+        if a.cursor > 0 {
+            a.cursor--
+        }
+
+    case "down", "j":
+        if a.cursor < len(a.entries) - 1 {
+            a.cursor++
+        }
+
+    case "enter":
+        m.agenda = nil
+
+        if !m.jumpTo(a.entries[a.cursor].item) {
+            m.status = "item is not part of the outline anymore"
+        }
+
+    case "esc", "q", "ctrl+c", "A":
+        m.agenda = nil
+    }
+}
+
+func (m model) agendaView() string {
+    a := m.agenda
+    now := time.Now()
+    today := startOfDay(now)
+
+    day_style := lipgloss.NewStyle().Bold(true)
+    overdue_style := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+    s := headerStyle.Render("Agenda") + "\n"
+
+    var day time.Time
+
+    for i, entry := range a.entries {
+        if !entry.day.Equal(day) {
+            day = entry.day
+            heading := day.Format(agendaDayLayout)
+
+            if day.Equal(today) {
+                heading += " (today)"
+            }
+
+            s += "\n" + day_style.Render(heading) + "\n"
+        }
+
+        line := fmt.Sprintf("%-9s %s %s", entry.key + ":", entry.item.GetTxt(), m.itemLocation(entry.item))
+
+        if i != a.cursor && metaDue == entry.key && IsOverdue(entry.item, now) {
+            line = overdue_style.Render(line)
+        }
+
+        s += listLine(line, i == a.cursor)
+    }
+
+    s += "\nenter: go to item   esc: back to the outline\n"
+
+    return s
+}
@@ -0,0 +1,112 @@
+package goutlinelib
+
+import (
+    "testing"
+    "time"
+)
+
+func TestParseDate(t *testing.T) {
+    // a Wednesday
+    now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.Local)
+
+    for value, expected := range map[string]string{
+        "today": "2026-10-14 00:00",
+        "Tomorrow": "2026-10-15 00:00",
+        "+3d": "2026-10-17 00:00",
+        "-1w": "2026-10-07 00:00",
+        "in 2 months": "2026-12-14 00:00",
+        "next week": "2026-10-21 00:00",
+        "fri": "2026-10-16 00:00",
+        "next wednesday": "2026-10-21 00:00",
+        "2026-11-01": "2026-11-01 00:00",
+        "2026-11-01 14:00": "2026-11-01 14:00",
+    } {
+        date, err := ParseDate(value, now)
+
+        if err != nil || date.Format(metaDateTimeLayout) != expected {
+            t.Error("Expected", expected, "for", value, "but got", date, err)
+        }
+    }
+
+    for _, value := range []string{"", "soon", "3", "2026-13-01"} {
+        if _, err := ParseDate(value, now); err == nil {
+            t.Error("Expected error for", value)
+        }
+    }
+}
+
+func TestDatesSurviveCopyAndUndo(t *testing.T) {
+    m := InitialModel()
+    item := m.Title.GetSubs()[0]
+
+    m.SetDate(item, metaDue)
+    m.prompt.onConfirm(&m, "2026-11-01")
+
+    if due, ok := MetaDateValue(item, metaDue); !ok || due.Day() != 1 || !m.IsDirty() {
+        t.Fatal("Expected due date to be set, but got", due)
+    }
+
+    if value, _ := MetaValue(item.DeepCopy(), metaDue); value != "2026-11-01" {
+        t.Error("Expected copy to keep the due date, but got", value)
+    }
+
+    m.SetDate(item, metaDue)
+    m.prompt.onConfirm(&m, "")
+
+    if _, ok := MetaDateValue(item, metaDue); ok {
+        t.Error("Expected due date to be removed")
+    }
+
+    m.PopUndo()
+
+    if value, _ := MetaValue(m.Title.GetSubs()[0], metaDue); value != "2026-11-01" {
+        t.Error("Expected undo to restore the due date, but got", value)
+    }
+
+    m.SetDate(m.Title.GetSubs()[0], metaScheduled)
+    m.prompt.onConfirm(&m, "someday")
+
+    if "" == m.status {
+        t.Error("Expected unknown date to be reported")
+    }
+}
+
+func TestAgenda(t *testing.T) {
+    m := InitialModel()
+    now := time.Now()
+
+    late := m.Title.GetSubs()[0]
+    SetMetaDate(late, metaDue, now.AddDate(0, 0, -2))
+    soon := m.AddNewItem(m.Title)
+    SetMetaDate(soon, metaScheduled, now)
+    SetMetaDate(soon, metaDue, now.AddDate(0, 0, 1))
+    done := m.AddNewItem(m.Title)
+    SetMetaDate(done, metaDue, now.AddDate(0, 0, -1))
+    done.SetChecked(true)
+
+    if !IsOverdue(late, now) || IsOverdue(soon, now) || IsOverdue(done, now) {
+        t.Error("Unexpected overdue states")
+    }
+
+    entries := m.agendaEntries()
+
+    if len(entries) != 3 || entries[0].item != late || entries[1].key != metaScheduled || entries[2].item != soon {
+        t.Fatal("Expected unchecked dated items by day, but got", entries)
+    }
+
+    if err := m.SetFilter("due:.." + now.Format(filterDateLayout)); err != nil || len(m.linearized) != 2 {
+        t.Error("Expected items due until today, but got", m.linearized, err)
+    }
+}
+
+func TestLinksHaveNoDates(t *testing.T) {
+    m := InitialModel()
+    link := NewLink(m.Title.GetSubs()[0])
+    m.AddSubAfterThis(m.Title.GetSubs()[0], link)
+
+    m.SetDate(link, metaDue)
+
+    if nil != m.prompt || "" == m.status {
+        t.Error("Expected dates of links not to be set")
+    }
+}
@@ -0,0 +1,52 @@
+package goutlinelib
+
+import(
+    "fmt"
+)
+
+// keys that modify the item under the cursor or its surroundings, which is not
+// possible for generated items
+var modifyingKeys = map[string]bool{
+    "i": true, "x": true, "t": true, "L": true, "v": true, "E": true, "!": true, "#": true, "p": true, "D": true, "S": true, "X": true,
+    "delete": true, "d": true, "backspace": true,
+    "tab": true, "shift+tab": true, "ctrl+k": true, "ctrl+j": true,
+    "ctrl+p": true, "enter": true, "o": true, " ": true,
+}
+
+// IsGenerated tells whether item has been generated from an external source.
+func (m *model) IsGenerated(item OItem) bool {
+    return nil != m.externalOf(item.GetParent())
+}
+
+// externalOf returns item itself or its nearest ancestor that is an external
+// item, if any.
+func (m *model) externalOf(item OItem) *oitemexternal {
+    for cur := item; nil != cur; cur = cur.GetParent() {
+        if external, ok := cur.(*oitemexternal); ok {
+            return external
+        }
+    }
+
+    return nil
+}
+
+// RefreshExternal loads the subs of the external item at or above item again.
+func (m *model) RefreshExternal(item OItem) {
+    external := m.externalOf(item)
+
+    if nil == external {
+        m.status = "not an external item"
+        return
+    }
+
+    external.Refresh()
+    m.UpdateLinearizedMapping()
+
+    if -1 == m.PosInLinearized(item) {
+        m.RevealItem(external)
+    } else {
+        m.Cursor = m.PosInLinearized(item)
+    }
+
+    m.status = fmt.Sprintf("refreshed %s", external.Source)
+}
@@ -0,0 +1,158 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestExternalTextFile(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    if err := ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("a\n  a1\n\n  a2\nb\n"), 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "outline.json"))
+
+    external := NewExternal("notes.txt")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], external)
+
+    if len(external.GetSubs()) != 0 {
+        t.Error("Expected external item not to be loaded before expanding it")
+    }
+
+    m.Expand(external)
+    subs := external.GetSubs()
+
+    if len(subs) != 2 || subs[0].GetTxt() != "a" || len(subs[0].GetSubs()) != 2 || subs[1].GetTxt() != "b" {
+        t.Fatal("Expected lines nested by indentation, but got", subs)
+    }
+
+    if !m.IsGenerated(subs[0].GetSubs()[1]) || m.IsGenerated(external) {
+        t.Error("Expected only the subs to be generated")
+    }
+
+    if err := ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("c\n"), 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m.RefreshExternal(subs[0])
+
+    if subs := external.GetSubs(); len(subs) != 1 || subs[0].GetTxt() != "c" {
+        t.Error("Expected refreshed contents, but got", subs)
+    }
+
+    b, err := json.Marshal(m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if strings.Contains(string(b), `"c"`) || !strings.Contains(string(b), `"Source":"notes.txt"`) {
+        t.Error("Expected external item to be saved as a reference, but got", string(b))
+    }
+}
+
+func TestExternalDirAndOutline(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if err := ioutil.WriteFile(filepath.Join(dir, "sub", "other.md"), []byte("- x\n  - y\n"), 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "outline.json"))
+
+    external := NewExternal(".")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], external)
+    m.Expand(external)
+
+    var sub OItem
+
+    for _, cur := range external.GetSubs() {
+        if cur.GetTxt() == "sub/" {
+            sub = cur
+        }
+    }
+
+    if nil == sub || sub.GetType() != OTypeProxyExternal {
+        t.Fatal("Expected subdirectory as external item, but got", external.GetSubs())
+    }
+
+    m.Expand(sub)
+
+    if subs := sub.GetSubs(); len(subs) != 1 || subs[0].GetTxt() != "other.md" {
+        t.Fatal("Expected listing of the subdirectory, but got", subs)
+    }
+
+    mounted := NewExternal(filepath.Join("sub", "other.md"))
+    m.AddSubAfterThis(external, mounted)
+    m.Expand(mounted)
+
+    if subs := mounted.GetSubs(); len(subs) != 1 || subs[0].GetTxt() != "x" || len(subs[0].GetSubs()) != 1 {
+        t.Error("Expected mounted outline, but got", subs)
+    }
+}
+
+func TestExternalMissingSource(t *testing.T) {
+    m := InitialModel()
+    m.SetFilename(filepath.Join(os.TempDir(), "outline.json"))
+
+    external := NewExternal("does-not-exist.txt")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], external)
+    m.Expand(external)
+
+    if subs := external.GetSubs(); len(subs) != 1 || !strings.HasPrefix(subs[0].GetTxt(), "⚠") {
+        t.Error("Expected error as sub, but got", subs)
+    }
+}
+
+func TestExternalOutlineMountingItself(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    self := `{"Title": {"Subs": [{"Txt": "a"}, {"Type": "oitemexternal", "Source": "self.json", "Expanded": true}]}}`
+
+    if err := ioutil.WriteFile(filepath.Join(dir, "self.json"), []byte(self), 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "outline.json"))
+
+    external := NewExternal("self.json")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], external)
+    m.Expand(external)
+
+    subs := external.GetSubs()
+
+    if len(subs) != 2 || subs[0].GetTxt() != "a" {
+        t.Fatal("Expected mounted outline, but got", subs)
+    }
+
+    if nested := subs[1].GetSubs(); len(nested) != 1 || !strings.Contains(nested[0].GetTxt(), "cycle") {
+        t.Error("Expected cycle to be reported instead of mounting again, but got", nested)
+    }
+}
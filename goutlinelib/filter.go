@@ -0,0 +1,336 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "strings"
+    "time"
+)
+
+// Filter decides which items are shown; the ancestors of matching items are
+// shown as well, to keep the context.
+type Filter interface {
+    Matches(m *model, item OItem) bool
+}
+
+type textFilter struct {
+    txt string
+}
+
+func (f textFilter) Matches(m *model, item OItem) bool {
+    return containsFold(item.GetTxt(), f.txt) || containsFold(item.GetBody(), f.txt)
+}
+
+type checkedFilter struct {
+    checked bool
+}
+
+func (f checkedFilter) Matches(m *model, item OItem) bool {
+    return item.IsChecked() == f.checked
+}
+
+type taskFilter struct {
+}
+
+func (f taskFilter) Matches(m *model, item OItem) bool {
+    return IsTask(item)
+}
+
+type tagFilter struct {
+    tag string
+}
+
+func (f tagFilter) Matches(m *model, item OItem) bool {
+    return m.HasTag(item, f.tag)
+}
+
+// matches items whose timestamp is within [from, to); zero means unbounded
+type dateFilter struct {
+    timestamp func(item OItem) int64
+    from int64
+    to int64
+}
+
+func (f dateFilter) Matches(m *model, item OItem) bool {
+    ts := f.timestamp(item)
+
+    if 0 == ts {
+        return false
+    }
+
+    return (0 == f.from || ts >= f.from) && (0 == f.to || ts < f.to)
+}
+
+type notFilter struct {
+    filter Filter
+}
+
+func (f notFilter) Matches(m *model, item OItem) bool {
+    return !f.filter.Matches(m, item)
+}
+
+type andFilter struct {
+    filters []Filter
+}
+
+func (f andFilter) Matches(m *model, item OItem) bool {
+    for _, cur := range f.filters {
+        if !cur.Matches(m, item) {
+            return false
+        }
+    }
+
+    return true
+}
+
+const filterDateLayout = "2006-01-02"
+
+// parseDateRange parses "DATE", "DATE..DATE", "DATE.." or "..DATE" (dates
+// as YYYY-MM-DD in local time, both ends inclusive).
+func parseDateRange(value string) (from int64, to int64, err error) {
+    parseDay := func(s string) (time.Time, error) {
+        return time.ParseInLocation(filterDateLayout, s, time.Local)
+    }
+
+    start, end := value, value
+
+    if idx := strings.Index(value, ".."); -1 != idx {
+        start, end = value[:idx], value[idx + 2:]
+    }
+
+    if "" != start {
+        day, err := parseDay(start)
+
+        if nil != err {
+            return 0, 0, fmt.Errorf("invalid date %q", start)
+        }
+
+        from = day.Unix()
+    }
+
+    if "" != end {
+        day, err := parseDay(end)
+
+        if nil != err {
+            return 0, 0, fmt.Errorf("invalid date %q", end)
+        }
+
+        to = day.AddDate(0, 0, 1).Unix()
+    }
+
+    return
+}
+
+func parseFilterTerm(term string) (Filter, error) {
+    if strings.HasPrefix(term, "-") && len(term) > 1 {
+        filter, err := parseFilterTerm(term[1:])
+
+        if nil != err {
+            return nil, err
+        }
+
+        return notFilter{filter: filter}, nil
+    }
+
+    if strings.HasPrefix(term, "#") && len(term) > 1 {
+        return tagFilter{tag: term[1:]}, nil
+    }
+
+    idx := strings.Index(term, ":")
+
+    if -1 == idx {
+        return textFilter{txt: term}, nil
+    }
+
+    key, value := strings.ToLower(term[:idx]), term[idx + 1:]
+
+    switch key {
+
+    case "is":
+        switch strings.ToLower(value) {
+
+        case "checked", "done":
+            return checkedFilter{checked: true}, nil
+
+        case "unchecked", "open":
+            return checkedFilter{checked: false}, nil
+
+        case "task":
+            return taskFilter{}, nil
+        }
+
+        return nil, fmt.Errorf("unknown state %q (checked, unchecked, task)", value)
+
+    case "tag":
+        return tagFilter{tag: value}, nil
+
+    case "created", "changed":
+        from, to, err := parseDateRange(value)
+
+        if nil != err {
+            return nil, err
+        }
+
+        timestamp := OItem.GetCreated
+
+        if "changed" == key {
+            timestamp = OItem.GetChanged
+        }
+
+        return dateFilter{timestamp: timestamp, from: from, to: to}, nil
+
+    case metaDue, metaScheduled:
+        from, to, err := parseDateRange(value)
+
+        if nil != err {
+            return nil, err
+        }
+
+        timestamp := func(item OItem) int64 {
+            if date, ok := MetaDateValue(item, key); ok {
+                return date.Unix()
+            }
+
+            return 0
+        }
+
+        return dateFilter{timestamp: timestamp, from: from, to: to}, nil
+    }
+
+    // e.g. a time of day
+    return textFilter{txt: term}, nil
+}
+
+// ParseFilter parses a filter expression: space separated terms that all have
+// to match. Terms are text to search for, "#tag" or "tag:tag", "is:checked",
+// "is:unchecked", "is:task", "created:RANGE", "changed:RANGE", "due:RANGE"
+// and "scheduled:RANGE" (see parseDateRange); "-" in front of a term negates
+// it.
+func ParseFilter(expr string) (Filter, error) {
+    var filters []Filter
+
+    for _, term := range strings.Fields(expr) {
+        filter, err := parseFilterTerm(term)
+
+        if nil != err {
+            return nil, err
+        }
+
+        filters = append(filters, filter)
+    }
+
+    if 0 == len(filters) {
+        return nil, nil
+    }
+
+    return andFilter{filters: filters}, nil
+}
+
+// filterVisible returns the items to show for filter: the matching items and
+// their ancestors.
+func (m *model) filterVisible(filter Filter) map[OItem]bool {
+    result := make(map[OItem]bool)
+
+    var walk func(item OItem) bool
+
+    walk = func(item OItem) bool {
+        visible := filter.Matches(m, item) || m.filterKeep[item]
+
+        // transcluded subs only repeat their target, generated ones are
+        // only searched while loaded
+        _, isProxy := item.(*oitemproxy)
+
+        if !isProxy && (storesSubs(item) || item.IsExpanded()) {
+            for _, sub := range item.GetSubs() {
+                if walk(sub) {
+                    visible = true
+                }
+            }
+        }
+
+        if visible {
+            result[item] = true
+        }
+
+        return visible
+    }
+
+    for _, sub := range m.Title.GetSubs() {
+        walk(sub)
+    }
+
+    return result
+}
+
+// SetFilter shows only the items matching expr (and their ancestors); an
+// empty expression shows all items again.
+func (m *model) SetFilter(expr string) error {
+    filter, err := ParseFilter(expr)
+
+    if nil != err {
+        return err
+    }
+
+    return m.applyFilter(filter, expr)
+}
+
+// applyFilter shows only the items matching filter, which has been described
+// by expr.
+func (m *model) applyFilter(filter Filter, expr string) error {
+    if nil != filter && 0 == len(m.filterVisible(filter)) {
+        return fmt.Errorf("no items match %q", expr)
+    }
+
+    cur := m.linearized[m.Cursor]
+
+    m.filter = filter
+    m.filterExpr = strings.TrimSpace(expr)
+    m.filterKeep = make(map[OItem]bool)
+
+    if nil == filter {
+        m.filterExpr = ""
+    }
+
+    m.UpdateLinearizedMapping()
+
+    if pos := m.PosInLinearized(cur); -1 != pos {
+        m.Cursor = pos
+    } else {
+        m.Cursor = 0
+    }
+
+    return nil
+}
+
+// keepVisible shows an item added while filtering, so that it can be edited
+// even though it does not match.
+func (m *model) keepVisible(item OItem) {
+    if nil != m.filter {
+        m.filterKeep[item] = true
+    }
+}
+
+// followFilterKeep looks up the items kept visible again after the outline has
+// been replaced by a copy (undo and redo), as they are kept by pointer.
+func (m *model) followFilterKeep() {
+    keep := make(map[OItem]bool)
+
+    for item := range m.filterKeep {
+        if "" == item.GetId() {
+            continue
+        }
+
+        if found := m.ItemById(item.GetId()); nil != found {
+            keep[found] = true
+        }
+    }
+
+    m.filterKeep = keep
+}
+
+func (m *model) OpenFilterPrompt() {
+    m.OpenPrompt("filter: ", m.filterExpr, func(m *model, expr string) {
+        if err := m.SetFilter(expr); nil != err {
+            m.status = err.Error()
+        }
+    })
+}
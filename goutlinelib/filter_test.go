@@ -0,0 +1,120 @@
+package goutlinelib
+
+import (
+    "testing"
+    "time"
+)
+
+func TestFilterKeepsAncestorsWithoutExpanding(t *testing.T) {
+    m := InitialModel()
+    work := m.Title.GetSubs()[0]
+    work.SetTxt("work")
+    report := m.AddNewItem(work)
+    report.SetTxt("write report")
+    SetMetaValue(report, metaTags, "urgent, office")
+    done := m.AddNewItem(work)
+    done.SetTxt("send mail")
+    done.SetChecked(true)
+    m.AddNewItem(m.Title).SetTxt("home")
+
+    if err := m.SetFilter("#URGENT"); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if len(m.linearized) != 2 || m.linearized[0] != work || m.linearized[1] != report {
+        t.Fatal("Expected only the match and its ancestor, but got", m.linearized)
+    }
+
+    if work.IsExpanded() {
+        t.Error("Expected filter not to change the expansion state")
+    }
+
+    if err := m.SetFilter("is:checked -report"); err != nil || len(m.linearized) != 2 || m.linearized[1] != done {
+        t.Error("Expected only the checked item, but got", m.linearized, err)
+    }
+
+    if err := m.SetFilter(""); err != nil || len(m.linearized) != 2 || nil != m.filter {
+        t.Error("Expected all (collapsed) items after clearing the filter, but got", m.linearized, err)
+    }
+}
+
+func TestFilterErrors(t *testing.T) {
+    m := InitialModel()
+
+    if err := m.SetFilter("nothing-matches-this"); err == nil || nil != m.filter {
+        t.Error("Expected error for a filter without matches")
+    }
+
+    if err := m.SetFilter("is:whatever"); err == nil {
+        t.Error("Expected error for an unknown state")
+    }
+
+    if err := m.SetFilter("created:2020-13-01"); err == nil {
+        t.Error("Expected error for an invalid date")
+    }
+}
+
+func TestFilterDateRange(t *testing.T) {
+    m := InitialModel()
+    report := m.AddNewItem(m.Title.GetSubs()[0])
+    report.SetTxt("write report")
+    today := time.Now().Format(filterDateLayout)
+
+    if err := m.SetFilter("changed:..2000-01-01"); err == nil {
+        t.Error("Expected no items changed before 2000")
+    }
+
+    if err := m.SetFilter("created:" + today + " report"); err != nil || m.linearized[len(m.linearized) - 1] != report {
+        t.Error("Expected item created today, but got", m.linearized, err)
+    }
+}
+
+func TestItemsAddedWhileFilteringStayVisible(t *testing.T) {
+    m := InitialModel()
+    report := m.AddNewItem(m.Title.GetSubs()[0])
+    report.SetTxt("write report")
+
+    if err := m.SetFilter("report"); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    added := m.AddNewItemAfterCurrentAndEdit(report)
+
+    if -1 == m.PosInLinearized(added) {
+        t.Error("Expected new item to be visible")
+    }
+
+    m.DeleteItem(added)
+    m.DeleteItem(report)
+
+    if nil != m.filter || len(m.linearized) == 0 {
+        t.Error("Expected filter to be removed when nothing matches anymore")
+    }
+}
+
+func TestItemsAddedWhileFilteringSurviveUndo(t *testing.T) {
+    m := InitialModel()
+    report := m.AddNewItem(m.Title.GetSubs()[0])
+    report.SetTxt("write report")
+
+    if err := m.SetFilter("report"); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    added := m.AddNewItemAfterCurrentAndEdit(report)
+    m.PushUndo()
+    report.SetTxt("write report soon")
+    m.PopUndo()
+
+    found := m.ItemById(added.GetId())
+
+    if nil == found || found == added || -1 == m.PosInLinearized(found) {
+        t.Error("Expected the restored copy of the new item to stay visible, but got", m.linearized)
+    }
+
+    m.Redo()
+
+    if -1 == m.PosInLinearized(m.ItemById(added.GetId())) {
+        t.Error("Expected the new item to stay visible after redo, but got", m.linearized)
+    }
+}
@@ -0,0 +1,79 @@
+package goutlinelib
+
+import(
+    "encoding/json"
+    "path/filepath"
+    "strings"
+)
+
+type FileFormat int
+
+const (
+    // goutline's own JSON layout
+    FileFormatJSON FileFormat = iota
+
+    // org-mode document
+    FileFormatOrg
+
+    // OPML 2.0 document
+    FileFormatOPML
+
+    // Markdown document (structure only)
+    FileFormatMarkdown
+)
+
+// FormatForFilename picks the file format from the file extension; anything
+// unknown is treated as JSON.
+func FormatForFilename(filename string) FileFormat {
+    switch strings.ToLower(filepath.Ext(filename)) {
+
+    case ".org":
+        return FileFormatOrg
+
+    case ".opml":
+        return FileFormatOPML
+
+    case ".md", ".markdown":
+        return FileFormatMarkdown
+    }
+
+    return FileFormatJSON
+}
+
+// ExportFilename derives the name of an exported file from the name of the
+// current file by replacing its extension.
+func ExportFilename(filename string, ext string) string {
+    return strings.TrimSuffix(filename, filepath.Ext(filename)) + ext
+}
+
+func (m *model) unmarshalFormat(format FileFormat, data []byte) error {
+    switch format {
+
+    case FileFormatOrg:
+        return m.UnmarshalOrg(data)
+
+    case FileFormatOPML:
+        return m.UnmarshalOPML(data)
+
+    case FileFormatMarkdown:
+        return m.UnmarshalMarkdown(data)
+    }
+
+    return json.Unmarshal(data, m)
+}
+
+func (m model) marshalFormat(format FileFormat) ([]byte, error) {
+    switch format {
+
+    case FileFormatOrg:
+        return m.MarshalOrg()
+
+    case FileFormatOPML:
+        return m.MarshalOPML()
+
+    case FileFormatMarkdown:
+        return m.MarshalMarkdown()
+    }
+
+    return json.MarshalIndent(m, "", "    ")
+}
@@ -0,0 +1,129 @@
+package goutlinelib
+
+import(
+    "strings"
+)
+
+// config key for the id of the hoisted item, so that it is hoisted again when
+// the file is opened
+const configHoist = "hoist"
+
+// Hoist shows only the subs of item, as if it was the title.
+func (m *model) Hoist(item OItem) {
+    if !item.HasSubs() {
+        m.status = "only items with subs can be hoisted"
+        return
+    }
+
+    m.setHoisted(item)
+    m.saveHoisted()
+    m.UpdateLinearizedMapping()
+    m.Cursor = 0
+}
+
+// StepUp hoists the parent of the hoisted item instead.
+func (m *model) StepUp() {
+    if nil == m.hoisted {
+        return
+    }
+
+    previous := m.hoisted
+    m.setHoisted(m.hoisted.GetParent())
+    m.saveHoisted()
+    m.UpdateLinearizedMapping()
+
+    if pos := m.PosInLinearized(previous); -1 != pos {
+        m.Cursor = pos
+    }
+}
+
+// Unhoist shows the whole outline again.
+func (m *model) Unhoist() {
+    cur := m.linearized[m.Cursor]
+
+    m.setHoisted(nil)
+    m.saveHoisted()
+    m.UpdateLinearizedMapping()
+
+    if pos := m.PosInLinearized(cur); -1 != pos {
+        m.Cursor = pos
+    }
+}
+
+func (m *model) setHoisted(item OItem) {
+    if item == m.Title {
+        item = nil
+    }
+
+    m.hoisted = item
+
+    // e.g. external items only load their subs when expanded; also keeps the
+    // context when stepping up
+    if nil != item {
+        item.SetExpanded(true)
+    }
+}
+
+// saveHoisted remembers the hoisted item in the config; only done when the
+// user hoists, not when the hoisted item changes as a consequence of other
+// changes (which are saved as modifications anyway).
+func (m *model) saveHoisted() {
+    // generated items cannot be found again by their id
+    if nil == m.hoisted || "" == m.hoisted.GetId() {
+        DeleteMetaValue(m.Config, configHoist)
+    } else {
+        m.SetConfigValue(configHoist, m.hoisted.GetId())
+    }
+
+    m.MarkDirty()
+}
+
+// restoreHoisted hoists the item saved in the config again, e.g. after loading.
+func (m *model) restoreHoisted() {
+    if id, found := m.ConfigValue(configHoist); found {
+        m.hoisted = m.ItemById(id)
+    }
+}
+
+// updateHoisted follows the hoisted item when the outline has been replaced
+// (e.g. by undo), and steps up if it has been deleted or has no subs anymore.
+func (m *model) updateHoisted() {
+    if nil == m.hoisted || "" == m.hoisted.GetId() {
+        return
+    }
+
+    hoisted := m.ItemById(m.hoisted.GetId())
+
+    for nil != hoisted && hoisted != m.Title && !hoisted.HasSubs() {
+        hoisted = hoisted.GetParent()
+    }
+
+    if hoisted != m.hoisted {
+        m.setHoisted(hoisted)
+    }
+}
+
+// isBelowHoisted tells whether item is shown while hoisting.
+func (m *model) isBelowHoisted(item OItem) bool {
+    return nil == m.hoisted || m.hoisted.HasSub(item)
+}
+
+// viewLevel returns the level of item relative to the hoisted item.
+func (m *model) viewLevel(item OItem) int {
+    return item.Level(m.hoisted)
+}
+
+// breadcrumb returns the path from the title to the hoisted item.
+func (m *model) breadcrumb() string {
+    var path []string
+
+    for cur := m.hoisted; nil != cur; cur = cur.GetParent() {
+        path = append([]string{cur.GetTxt()}, path...)
+    }
+
+    if 0 == len(path) {
+        return m.Title.GetTxt()
+    }
+
+    return strings.Join(path, " › ")
+}
@@ -0,0 +1,141 @@
+package goutlinelib
+
+import (
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestHoist(t *testing.T) {
+    m := InitialModel()
+    parent := m.AddNewItem(m.Title)
+    parent.SetTxt("parent")
+    child := m.AddNewItem(parent)
+    child.SetTxt("child")
+    grandchild := m.AddNewItem(child)
+    m.Expand(child)
+
+    m.Hoist(child)
+
+    if len(m.linearized) != 1 || m.linearized[0] != grandchild {
+        t.Fatal("Expected only the subs of the hoisted item, but got", m.linearized)
+    }
+
+    if level := m.viewLevel(grandchild); level != 1 {
+        t.Error("Expected level relative to the hoisted item, but got", level)
+    }
+
+    if crumbs := m.breadcrumb(); crumbs != m.Title.GetTxt() + " › parent › child" {
+        t.Error("Unexpected breadcrumb", crumbs)
+    }
+
+    m.Demote(grandchild)
+
+    if grandchild.GetParent() != child {
+        t.Error("Expected item not to leave the hoisted item")
+    }
+
+    m.StepUp()
+
+    if m.hoisted != parent || m.linearized[m.Cursor] != child {
+        t.Error("Expected parent to be hoisted with the cursor on the previously hoisted item")
+    }
+
+    m.StepUp()
+
+    if nil != m.hoisted || len(m.linearized) != 4 {
+        t.Error("Expected whole outline after stepping up to the title, but got", m.linearized)
+    }
+
+    m.Hoist(grandchild)
+
+    if nil != m.hoisted {
+        t.Error("Expected items without subs not to be hoisted")
+    }
+}
+
+func TestHoistFollowsChanges(t *testing.T) {
+    m := InitialModel()
+    parent := m.AddNewItem(m.Title)
+    child := m.AddNewItem(parent)
+
+    m.Hoist(parent)
+    m.PushUndo()
+    m.DeleteItem(child)
+
+    if nil != m.hoisted {
+        t.Error("Expected unhoisting when the hoisted item has no subs anymore")
+    }
+
+    m.Hoist(parent)
+
+    if m.hoisted == parent {
+        t.Error("Expected item without subs not to be hoisted")
+    }
+
+    m.PopUndo()
+    m.Hoist(m.Title.GetSubs()[1])
+    m.PushUndo()
+    m.PopUndo()
+
+    if nil == m.hoisted || m.hoisted.GetId() != parent.GetId() || m.hoisted == parent || len(m.linearized) != 1 {
+        t.Error("Expected hoisting to follow the restored copy after undo")
+    }
+
+    m.RevealItem(m.Title.GetSubs()[0])
+
+    if nil != m.hoisted {
+        t.Error("Expected unhoisting to reveal an item outside of the hoisted one")
+    }
+}
+
+func TestHoistIsRestored(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    m := InitialModel()
+    m.SetFilename(filepath.Join(dir, "out.json"))
+    parent := m.AddNewItem(m.Title)
+    m.AddNewItem(parent)
+    m.Hoist(parent)
+
+    if !m.Save() {
+        t.Fatal("Expected successful save")
+    }
+
+    loaded, err := ModelFromFile(m.filename)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if nil == loaded.hoisted || loaded.hoisted.GetId() != parent.GetId() || len(loaded.linearized) != 1 {
+        t.Error("Expected hoisted item to be restored, but got", loaded.hoisted)
+    }
+}
+
+func TestHoistConfigOnlyChangedByHoisting(t *testing.T) {
+    m := InitialModel()
+    parent := m.AddNewItem(m.Title)
+    child := m.AddNewItem(parent)
+
+    m.dirty = false
+    m.Hoist(parent)
+
+    if id, _ := m.ConfigValue(configHoist); id != parent.GetId() || !m.IsDirty() {
+        t.Fatal("Expected hoisting to be remembered as a modification, but got", id)
+    }
+
+    // the hoisted item loses its subs without anyone hoisting
+    parent.(*oitem).Subs = nil
+    child.SetParent(nil)
+    m.dirty = false
+    m.UpdateLinearizedMapping()
+
+    if id, _ := m.ConfigValue(configHoist); nil != m.hoisted || id != parent.GetId() || m.IsDirty() {
+        t.Error("Expected the config to be left alone when stepping up by itself, but got", id)
+    }
+}
@@ -0,0 +1,150 @@
+package goutlinelib
+
+import(
+    "bytes"
+    "fmt"
+    "html"
+    "strings"
+)
+
+// colors approximate the ANSI colors used by contentView and drawItem
+const htmlStyle = `
+body { font-family: "Pragmata Pro", Menlo, Consolas, monospace; background: #1c1c1c; color: #eeeeee; margin: 2em; }
+h1 { background: #ffff5f; color: #000000; font-size: 1em; font-weight: normal; padding: 0 0.5em; display: inline-block; }
+ul, ol { margin: 0; padding-left: 1.5em; }
+li { margin: 0.1em 0; }
+ul { list-style-type: "· "; }
+details > summary { cursor: pointer; }
+details > summary::marker { color: #5f5fff; }
+.checked { text-decoration: line-through; color: #8a8a8a; }
+.check { color: #5f5fff; }
+.body { white-space: pre-wrap; color: #8a8a8a; margin: 0.2em 0 0.4em 0; }
+`
+
+type htmlExportVisitor struct {
+    buf bytes.Buffer
+
+    // closing tags of the items that have not been closed yet, by level
+    open []string
+}
+
+func (v *htmlExportVisitor) closeUntil(level int) {
+    for len(v.open) > level {
+        v.buf.WriteString(v.open[len(v.open) - 1])
+        v.open = v.open[:len(v.open) - 1]
+    }
+}
+
+func (v *htmlExportVisitor) VisitTitle(m *model, item OItem) error {
+    title := html.EscapeString(item.GetTxt())
+
+    fmt.Fprintf(&v.buf, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n<h1>%s</h1>\n", title, htmlStyle, title)
+
+    if "" != item.GetBody() {
+        fmt.Fprintf(&v.buf, "<div class=\"body\">%s</div>\n", html.EscapeString(strings.TrimRight(item.GetBody(), "\n")))
+    }
+
+    v.buf.WriteString(htmlListStart(item))
+    v.open = []string{htmlListEnd(item)}
+
+    return nil
+}
+
+func (v *htmlExportVisitor) VisitConfig(m *model, item OItem) error {
+    return nil
+}
+
+func htmlListStart(item OItem) string {
+    if item.IsNumbered() {
+        return "<ol>\n"
+    }
+
+    return "<ul>\n"
+}
+
+func htmlListEnd(item OItem) string {
+    if item.IsNumbered() {
+        return "</ol>\n"
+    }
+
+    return "</ul>\n"
+}
+
+func (v *htmlExportVisitor) VisitItem(m *model, item OItem, level int) error {
+    v.closeUntil(level)
+
+    txt := html.EscapeString(item.GetTxt())
+
+    if item.IsChecked() {
+        txt = "<span class=\"check\">✓</span> <span class=\"checked\">" + txt + "</span>"
+    }
+
+    body := ""
+
+    if "" != item.GetBody() {
+        body = fmt.Sprintf("<div class=\"body\">%s</div>", html.EscapeString(strings.TrimRight(item.GetBody(), "\n")))
+    }
+
+    if !item.HasSubs() {
+        fmt.Fprintf(&v.buf, "<li>%s%s</li>\n", txt, body)
+
+        // keep the levels aligned; nothing to close for this item
+        v.open = append(v.open, "")
+        return nil
+    }
+
+    open := ""
+
+    if item.IsExpanded() {
+        open = " open"
+    }
+
+    fmt.Fprintf(&v.buf, "<li><details%s><summary>%s</summary>%s\n%s", open, txt, body, htmlListStart(item))
+    v.open = append(v.open, htmlListEnd(item) + "</details></li>\n")
+
+    return nil
+}
+
+func (v *htmlExportVisitor) ShouldDescend(m *model, item OItem) bool {
+    return true
+}
+
+// MarshalHTML exports the outline as a single self-contained HTML page, with
+// collapsible items mirroring the current expansion state.
+func (m model) MarshalHTML() ([]byte, error) {
+    v := &htmlExportVisitor{}
+
+    err := m.VisitAll(v)
+
+    if nil != err {
+        return nil, fmt.Errorf("Could not export HTML: %w", err)
+    }
+
+    v.closeUntil(0)
+    v.buf.WriteString("</body>\n</html>\n")
+
+    return v.buf.Bytes(), nil
+}
+
+// ExportHTMLFile loads an outline in any supported format and writes it as HTML.
+func ExportHTMLFile(filename string, htmlFilename string) error {
+    m, err := ModelFromFile(filename)
+
+    if nil != err {
+        return err
+    }
+
+    b, err := m.MarshalHTML()
+
+    if nil != err {
+        return err
+    }
+
+    err = WriteFileAtomic(htmlFilename, b, 0)
+
+    if nil != err {
+        return fmt.Errorf("Could not write %s: %w", htmlFilename, err)
+    }
+
+    return nil
+}
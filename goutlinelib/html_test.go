@@ -0,0 +1,34 @@
+package goutlinelib
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestHTMLExport(t *testing.T) {
+    var m model
+    m.UnmarshalMarkdown([]byte("# T & t\n\n- a\n  - [x] b\n- c\n"))
+    m.Title.GetSubs()[0].SetExpanded(true)
+
+    b, err := m.MarshalHTML()
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    res := string(b)
+
+    for _, expected := range []string{
+        "<title>T &amp; t</title>",
+        "<li><details open><summary>a</summary>",
+        "<span class=\"checked\">b</span></li>",
+        "</ul>\n</details></li>\n<li>c</li>\n</ul>\n</body>"} {
+
+        if !strings.Contains(res, expected) {
+            t.Error("Expected", expected, "in", res)
+        }
+    }
+
+    if strings.Count(res, "<ul>") != strings.Count(res, "</ul>") || strings.Count(res, "<details") != strings.Count(res, "</details>") {
+        t.Error("Unbalanced elements in", res)
+    }
+}
@@ -0,0 +1,107 @@
+package goutlinelib
+
+import(
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "path/filepath"
+    "time"
+)
+
+// NewId returns a random id for an item, unique for all practical purposes.
+func NewId() string {
+    b := make([]byte, 8)
+
+    if _, err := rand.Read(b); err != nil {
+        // should never happen; still better than no id at all
+        return fmt.Sprintf("%016x", time.Now().UnixNano())
+    }
+
+    return hex.EncodeToString(b)
+}
+
+// assignMissingIds gives every item below (and including) root an id, e.g.
+// for items from files written before ids were assigned, or from imports.
+func assignMissingIds(root OItem) {
+    if nil == root {
+        return
+    }
+
+    if "" == root.GetId() {
+        root.SetId(NewId())
+    }
+
+    if !storesSubs(root) {
+        return
+    }
+
+    for _, sub := range root.GetSubs() {
+        assignMissingIds(sub)
+    }
+}
+
+// renewDuplicateIds gives the items below (and including) item that have the
+// id of another item in the outline a new one, e.g. for a cut item that is
+// pasted after undoing the cut.
+func (m *model) renewDuplicateIds(item OItem) {
+    if indexed, found := m.itemsById[item.GetId()]; found && indexed != item {
+        item.SetId(NewId())
+    }
+
+    if !storesSubs(item) {
+        return
+    }
+
+    for _, sub := range item.GetSubs() {
+        m.renewDuplicateIds(sub)
+    }
+}
+
+func (m *model) indexSubtree(item OItem) {
+    if "" != item.GetId() {
+        m.itemsById[item.GetId()] = item
+    }
+
+    m.addBacklink(item)
+
+    if external, ok := item.(*oitemexternal); ok {
+        external.setBase(filepath.Dir(m.filename))
+    }
+
+    if !storesSubs(item) {
+        return
+    }
+
+    for _, sub := range item.GetSubs() {
+        m.indexSubtree(sub)
+    }
+}
+
+func (m *model) unindexSubtree(item OItem) {
+    if indexed, found := m.itemsById[item.GetId()]; found && indexed == item {
+        delete(m.itemsById, item.GetId())
+    }
+
+    m.removeBacklink(item)
+
+    if !storesSubs(item) {
+        return
+    }
+
+    for _, sub := range item.GetSubs() {
+        m.unindexSubtree(sub)
+    }
+}
+
+// RebuildIdIndex indexes the whole outline (including backlinks) again, which
+// is necessary whenever the title is replaced (e.g. by undo).
+func (m *model) RebuildIdIndex() {
+    m.itemsById = make(map[string]OItem)
+    m.backlinks = make(map[string][]OItem)
+    m.indexSubtree(m.Title)
+}
+
+// ItemById returns the item with the given id, or nil if there is none.
+func (m *model) ItemById(id string) OItem {
+    return m.itemsById[id]
+}
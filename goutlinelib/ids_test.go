@@ -0,0 +1,94 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestNewItemsGetUniqueIds(t *testing.T) {
+    m := InitialModel()
+    first := m.Title.GetSubs()[0]
+    second := m.AddNewItem(m.Title)
+    copied := first.DeepCopy()
+
+    ids := map[string]bool{}
+
+    for _, item := range []OItem{first, second, copied} {
+        if item.GetId() == "" || ids[item.GetId()] {
+            t.Error("Expected unique id, but got", item.GetId())
+        }
+
+        ids[item.GetId()] = true
+    }
+}
+
+func TestLegacyItemsGetIdsOnLoad(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Version": 2, "Title": {"Subs": [{"Txt": "a", "Subs": [{"Txt": "b"}]}]}}`))
+    item := m.Title.GetSubs()[0].GetSubs()[0]
+
+    if item.GetId() == "" {
+        t.Fatal("Expected id to be assigned on load")
+    }
+
+    if m.ItemById(item.GetId()) != item {
+        t.Error("Expected item to be indexed by its id")
+    }
+}
+
+func TestIdIndexFollowsStructuralChanges(t *testing.T) {
+    m := InitialModel()
+    first := m.Title.GetSubs()[0]
+    second := m.AddNewItem(m.Title)
+    sub := m.AddNewItem(second)
+
+    if m.ItemById(sub.GetId()) != sub {
+        t.Fatal("Expected added item to be indexed")
+    }
+
+    m.DeleteItem(second)
+
+    if m.ItemById(second.GetId()) != nil || m.ItemById(sub.GetId()) != nil {
+        t.Error("Expected deleted items to be removed from the index")
+    }
+
+    m.AddSubAfterThis(first, second)
+
+    if m.ItemById(sub.GetId()) != sub {
+        t.Error("Expected pasted items to be indexed again")
+    }
+
+    m.PopUndo()
+
+    for id, item := range m.itemsById {
+        if item.GetId() != id || (item != m.Title && !m.Title.HasSub(item)) {
+            t.Error("Expected index to match the outline after undo, but found", id)
+        }
+    }
+}
+
+func TestPasteAfterUndoingCutGetsNewIds(t *testing.T) {
+    m := InitialModel()
+    cut := m.AddNewItem(m.Title)
+    m.AddNewItem(cut)
+
+    m.PushUndo()
+    copied := m.DeleteItem(cut)
+    m.PopUndo()
+
+    m.AddSubAfterThis(m.Title.GetSubs()[0], copied)
+
+    seen := make(map[string]int)
+
+    walkStored(m.Title, func(item OItem) {
+        seen[item.GetId()]++
+    })
+
+    for id, count := range seen {
+        if count != 1 {
+            t.Error("Expected unique ids, but", id, "is used", count, "times")
+        }
+    }
+
+    if m.ItemById(copied.GetId()) != copied || m.ItemById(copied.GetSubs()[0].GetId()) != copied.GetSubs()[0] {
+        t.Error("Expected the pasted items to be indexed by their new ids")
+    }
+}
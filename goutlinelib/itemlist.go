@@ -0,0 +1,91 @@
+package goutlinelib
+
+import(
+    "fmt"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// a list of items to choose one from and jump to it, e.g. broken links
+type itemList struct {
+    title string
+    items []OItem
+    cursor int
+
+    // text shown for an item in the list
+    describe func(item OItem) string
+}
+
+// itemLocation names the parent of an item, which is usually enough to tell
+// where it is.
+func (m *model) itemLocation(item OItem) string {
+    if parent := item.GetParent(); nil != parent && parent != m.Title {
+        return fmt.Sprintf("(below %q)", parent.GetTxt())
+    }
+
+    return "(top level)"
+}
+
+// OpenItemList shows the items to choose from; with no items, only the status
+// message empty is shown.
+func (m *model) OpenItemList(title string, items []OItem, describe func(item OItem) string, empty string) {
+    if 0 == len(items) {
+        m.status = empty
+        return
+    }
+
+    m.itemList = &itemList{title: title, items: items, describe: describe}
+}
+
+// jumpTo moves the cursor to item; the current item is remembered, so that
+// JumpBack can return to it.
+func (m *model) jumpTo(item OItem) bool {
+    if m.Cursor < len(m.linearized) && "" != m.linearized[m.Cursor].GetId() {
+        m.navHistory = append(m.navHistory, m.linearized[m.Cursor].GetId())
+    }
+
+    return m.RevealItem(item)
+}
+
+func (m *model) handleItemListKey(msg tea.KeyMsg) {
+    list := m.itemList
+
+    switch msg.String() {
+
+    case "up", "k":
+        if list.cursor > 0 {
+            list.cursor--
+        }
+
+    case "down", "j":
+        if list.cursor < len(list.items) - 1 {
+            list.cursor++
+        }
+
+    case "enter":
+        m.itemList = nil
+
+        if !m.jumpTo(list.items[list.cursor]) {
+            m.status = "item is not part of the outline anymore"
+        }
+
+    case "esc", "q", "ctrl+c":
+        m.itemList = nil
+    }
+}
+
+func (m model) itemListView() string {
+    list := m.itemList
+
+    s := headerStyle.Render(list.title) + "\n\n"
+
+    for i, item := range list.items {
+        line := list.describe(item)
+
+        s += listLine(line, i == list.cursor)
+    }
+
+    s += "\nenter: go to item   esc: cancel\n"
+
+    return s
+}
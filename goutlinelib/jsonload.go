@@ -0,0 +1,201 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "strings"
+)
+
+// LoadProblem describes a recoverable problem found when loading a file; the
+// affected value has been replaced by a default.
+type LoadProblem struct {
+    // location of the problem, e.g. "Title.Subs[3].Subs[0].Checked"
+    Path string
+
+    Message string
+}
+
+func (p LoadProblem) String() string {
+    return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// LoadError is returned alongside a usable (repaired) model when a file could
+// be loaded, but only by working around the listed problems.
+type LoadError struct {
+    Filename string
+    Problems []LoadProblem
+}
+
+func (e *LoadError) Error() string {
+    lines := make([]string, 0, len(e.Problems))
+
+    for _, problem := range e.Problems {
+        lines = append(lines, problem.String())
+    }
+
+    return fmt.Sprintf("%d problem(s) in %s:\n%s", len(e.Problems), e.Filename, strings.Join(lines, "\n"))
+}
+
+// jsonLoader collects problems while converting the generic JSON
+// representation into items, instead of stopping at the first one.
+type jsonLoader struct {
+    problems []LoadProblem
+}
+
+func (l *jsonLoader) problem(path string, format string, args ...interface{}) {
+    l.problems = append(l.problems, LoadProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func jsonPath(path string, key string) string {
+    if "" == path {
+        return key
+    }
+
+    return path + "." + key
+}
+
+func jsonTypeName(value interface{}) string {
+    switch value.(type) {
+
+    case nil:
+        return "null"
+
+    case bool:
+        return "bool"
+
+    case float64:
+        return "number"
+
+    case string:
+        return "string"
+
+    case []interface{}:
+        return "array"
+
+    case map[string]interface{}:
+        return "object"
+    }
+
+    return fmt.Sprintf("%T", value)
+}
+
+// the getters return the zero value for missing fields, and additionally
+// report a problem for fields of the wrong type
+
+func (l *jsonLoader) getString(temp map[string]interface{}, path string, key string) string {
+    value, found := temp[key]
+
+    if !found || nil == value {
+        return ""
+    }
+
+    result, ok := value.(string)
+
+    if !ok {
+        l.problem(jsonPath(path, key), "expected string, got %s", jsonTypeName(value))
+    }
+
+    return result
+}
+
+func (l *jsonLoader) getBool(temp map[string]interface{}, path string, key string) bool {
+    value, found := temp[key]
+
+    if !found || nil == value {
+        return false
+    }
+
+    result, ok := value.(bool)
+
+    if !ok {
+        l.problem(jsonPath(path, key), "expected bool, got %s", jsonTypeName(value))
+    }
+
+    return result
+}
+
+func (l *jsonLoader) getInt64(temp map[string]interface{}, path string, key string) int64 {
+    value, found := temp[key]
+
+    if !found || nil == value {
+        return 0
+    }
+
+    result, ok := value.(float64)
+
+    if !ok {
+        l.problem(jsonPath(path, key), "expected number, got %s", jsonTypeName(value))
+    }
+
+    return int64(result)
+}
+
+// item converts an object into an item; nil is returned (and a problem
+// reported) for anything that cannot be turned into one.
+func (l *jsonLoader) item(value interface{}, path string) OItem {
+    temp, ok := value.(map[string]interface{})
+
+    if !ok {
+        l.problem(path, "expected object, got %s", jsonTypeName(value))
+        return nil
+    }
+
+    typeName := l.getString(temp, path, "Type")
+
+    if "" == typeName || "oitem" == typeName {
+        result := &oitem{}
+        result.unmarshalOitemStruct(l, temp, path)
+
+        return result
+    }
+
+    if "oitemproxy" == typeName {
+        result := &oitemproxy{}
+        result.unmarshalProxyStruct(l, temp, path)
+
+        return result
+    }
+
+    if "oitemlink" == typeName {
+        result := &oitemlink{}
+        result.unmarshalLinkStruct(l, temp, path)
+
+        return result
+    }
+
+    if "oitemexternal" == typeName {
+        result := &oitemexternal{}
+        result.unmarshalExternalStruct(l, temp, path)
+
+        return result
+    }
+
+    l.problem(jsonPath(path, "Type"), "unsupported type %q", typeName)
+
+    return nil
+}
+
+func (l *jsonLoader) subs(temp map[string]interface{}, path string) []OItem {
+    value, found := temp["Subs"]
+
+    if !found || nil == value {
+        return nil
+    }
+
+    path = jsonPath(path, "Subs")
+    arr, ok := value.([]interface{})
+
+    if !ok {
+        l.problem(path, "expected array, got %s", jsonTypeName(value))
+        return nil
+    }
+
+    result := make([]OItem, 0, len(arr))
+
+    for i, cur := range arr {
+        if item := l.item(cur, fmt.Sprintf("%s[%d]", path, i)); nil != item {
+            result = append(result, item)
+        }
+    }
+
+    return result
+}
@@ -0,0 +1,70 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "errors"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestLoadReportsProblemsWithPath(t *testing.T) {
+    doc := `{
+        "Title": {"Txt": "t", "Subs": [
+            {"Txt": "a"},
+            {"Txt": "b", "Subs": [{"Txt": "b1", "Checked": "yes"}, 42]},
+            {"Type": "unknown"}
+        ]},
+        "Cursor": 7
+    }`
+
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer os.RemoveAll(dir)
+
+    filename := filepath.Join(dir, "broken.json")
+    ioutil.WriteFile(filename, []byte(doc), 0644)
+
+    m, err := ModelFromFile(filename)
+
+    var loadErr *LoadError
+    if !errors.As(err, &loadErr) {
+        t.Fatal("Expected LoadError, but got", err)
+    }
+
+    expected := []string{
+        "Title.Subs[1].Subs[0].Checked: expected bool, got string",
+        "Title.Subs[1].Subs[1]: expected object, got number",
+        "Title.Subs[2].Type: unsupported type \"unknown\"",
+        "Cursor: 7 is out of range"}
+
+    if len(loadErr.Problems) != len(expected) {
+        t.Fatal("Expected", expected, "but got", loadErr.Problems)
+    }
+
+    for i, problem := range loadErr.Problems {
+        if problem.String() != expected[i] {
+            t.Error("Expected", expected[i], "but got", problem.String())
+        }
+    }
+
+    if len(m.Title.GetSubs()) != 2 || m.Title.GetSubs()[1].GetSubs()[0].IsChecked() || m.Cursor != 0 {
+        t.Error("Expected repaired outline, but got", m.Title.GetSubs())
+    }
+}
+
+func TestLoadDefaultsMissingFields(t *testing.T) {
+    var m model
+
+    err := json.Unmarshal([]byte(`{"Title": {"Txt": "t", "Subs": [{"Txt": "a"}]}}`), &m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if len(m.loadProblems) != 0 || m.Title.GetSubs()[0].GetTxt() != "a" {
+        t.Error("Expected missing fields to be defaulted silently, but got", m.loadProblems)
+    }
+}
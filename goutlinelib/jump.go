@@ -0,0 +1,137 @@
+package goutlinelib
+
+import(
+    "strings"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/charmbracelet/lipgloss"
+)
+
+// characters used for jump labels, easiest to type first
+const jumpLabelChars = "asdfghjklqwertyuiopzxcvbnm"
+
+// lines above the first item in the content view (header and empty line)
+const contentHeaderLines = 2
+
+// labels shown next to the items while choosing one to jump to
+type jumpMode struct {
+    labels map[OItem]string
+
+    // first characters of a label typed so far
+    typed string
+}
+
+// jumpLabels returns n labels of the same length (so none is the prefix of
+// another): single characters if possible, two characters otherwise. There
+// are fewer labels than requested if not all can be labelled.
+func jumpLabels(n int) []string {
+    var result []string
+
+    if n <= len(jumpLabelChars) {
+        for i := 0; i < n; i++ {
+            result = append(result, jumpLabelChars[i:i + 1])
+        }
+
+        return result
+    }
+
+    for _, first := range jumpLabelChars {
+        for _, second := range jumpLabelChars {
+            if len(result) == n {
+                return result
+            }
+
+            result = append(result, string(first) + string(second))
+        }
+    }
+
+    return result
+}
+
+// StartJump labels the shown items, starting with the first one in the
+// viewport; typing a label moves the cursor to its item.
+func (m *model) StartJump() {
+    if len(m.linearized) < 2 {
+        m.status = "nothing to jump to"
+        return
+    }
+
+    first := m.viewport.YOffset - contentHeaderLines
+
+    if first < 0 || first >= len(m.linearized) {
+        first = 0
+    }
+
+    labels := jumpLabels(len(m.linearized))
+    jump := &jumpMode{labels: make(map[OItem]string)}
+
+    for i, label := range labels {
+        jump.labels[m.linearized[(first + i) % len(m.linearized)]] = label
+    }
+
+    m.jump = jump
+}
+
+// typeJumpKey narrows the labels down to the ones starting with the keys
+// typed so far, and jumps once a label is complete.
+func (m *model) typeJumpKey(key string) {
+    jump := m.jump
+
+    switch key {
+
+    case "esc", "ctrl+c":
+        m.jump = nil
+        return
+
+    case "backspace":
+        if len(jump.typed) > 0 {
+            jump.typed = jump.typed[:len(jump.typed) - 1]
+        }
+
+        return
+    }
+
+    typed := jump.typed + key
+    found := false
+
+    for item, label := range jump.labels {
+        if label == typed {
+            m.jump = nil
+            m.jumpTo(item)
+            return
+        }
+
+        if strings.HasPrefix(label, typed) {
+            found = true
+        }
+    }
+
+    if found {
+        jump.typed = typed
+    } else {
+        m.status = "no such label: " + typed
+    }
+}
+
+func (m *model) handleJumpKey(msg tea.KeyMsg) {
+    m.status = ""
+    m.typeJumpKey(msg.String())
+}
+
+// width of the cursor and check box, which the labels are shown in place of
+const jumpLabelWidth = 5
+
+// jumpLabelView returns the rest of the label of item to type, in place of
+// the cursor and check box.
+func (m *model) jumpLabelView(item OItem) (string, bool) {
+    label, ok := m.jump.labels[item]
+
+    if !ok || !strings.HasPrefix(label, m.jump.typed) {
+        return "", false
+    }
+
+    rest := label[len(m.jump.typed):]
+    style := lipgloss.NewStyle().Background(lipgloss.Color("196")).Foreground(lipgloss.Color("255")).Bold(true)
+
+    return style.Render(rest) + strings.Repeat(" ", jumpLabelWidth - len(rest)), true
+}
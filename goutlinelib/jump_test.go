@@ -0,0 +1,60 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestJumpLabels(t *testing.T) {
+    if labels := jumpLabels(3); len(labels) != 3 || labels[0] != "a" || labels[2] != "d" {
+        t.Error("Expected single character labels, but got", labels)
+    }
+
+    labels := jumpLabels(30)
+
+    if len(labels) != 30 || labels[0] != "aa" || labels[26] != "sa" {
+        t.Error("Expected two character labels, but got", labels)
+    }
+
+    if labels := jumpLabels(1000); len(labels) != len(jumpLabelChars) * len(jumpLabelChars) {
+        t.Error("Expected labels to be limited to two characters, but got", len(labels))
+    }
+}
+
+func TestJump(t *testing.T) {
+    m := InitialModel()
+
+    for i := 0; i < 30; i++ {
+        m.AddNewItem(m.Title)
+    }
+
+    m.viewport.YOffset = contentHeaderLines + 10
+    m.StartJump()
+
+    if label := m.jump.labels[m.linearized[10]]; label != "aa" {
+        t.Error("Expected labels to start at the first row in the viewport, but got", label)
+    }
+
+    m.typeJumpKey("s")
+
+    if _, ok := m.jumpLabelView(m.linearized[10]); ok {
+        t.Error("Expected labels not starting with the typed keys to be hidden")
+    }
+
+    m.typeJumpKey("x")
+
+    if nil == m.jump || m.jump.typed != "s" || "" == m.status {
+        t.Error("Expected unknown label to be reported and ignored")
+    }
+
+    m.typeJumpKey("d")
+
+    if nil != m.jump || m.Cursor != (10 + 28) % 31 {
+        t.Error("Expected jump to the labelled item, but cursor is at", m.Cursor)
+    }
+
+    m.JumpBack()
+
+    if m.Cursor != 0 {
+        t.Error("Expected to jump back to the previous item, but cursor is at", m.Cursor)
+    }
+}
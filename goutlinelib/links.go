@@ -0,0 +1,123 @@
+package goutlinelib
+
+import(
+    "fmt"
+)
+
+// LinkTarget returns the item a link points to, or nil if the link is broken
+// (or item is not a link).
+func (m *model) LinkTarget(item OItem) OItem {
+    link, ok := item.(*oitemlink)
+
+    if !ok {
+        return nil
+    }
+
+    target := m.ItemById(link.Target)
+
+    if nil != target {
+        // keep the text shown by the link up to date after undo and the like
+        link.target = target
+    }
+
+    return target
+}
+
+func (m *model) IsBrokenLink(item OItem) bool {
+    _, ok := item.(*oitemlink)
+
+    return ok && nil == m.LinkTarget(item)
+}
+
+// RevealItem expands all ancestors of item (unhoisting, if it is not below the
+// hoisted item) and moves the cursor to it.
+func (m *model) RevealItem(item OItem) bool {
+    if !m.isBelowHoisted(item) {
+        m.setHoisted(nil)
+    }
+
+    for cur := item.GetParent(); nil != cur; cur = cur.GetParent() {
+        cur.SetExpanded(true)
+    }
+
+    m.UpdateLinearizedMapping()
+
+    pos := m.PosInLinearized(item)
+
+    if -1 == pos {
+        return false
+    }
+
+    m.Cursor = pos
+
+    return true
+}
+
+// FollowLink jumps to the target of a link.
+func (m *model) FollowLink(item OItem) bool {
+    link, ok := item.(*oitemlink)
+
+    if !ok {
+        m.status = "not a link"
+        return false
+    }
+
+    target := m.LinkTarget(link)
+
+    if nil == target {
+        m.status = fmt.Sprintf("broken link: no item with id %s", link.Target)
+        return false
+    }
+
+    return m.jumpTo(target)
+}
+
+// JumpBack returns to the position before the most recent jump; items that
+// have been deleted in the meantime are skipped.
+func (m *model) JumpBack() bool {
+    for len(m.navHistory) > 0 {
+        id := m.navHistory[len(m.navHistory) - 1]
+        m.navHistory = m.navHistory[:len(m.navHistory) - 1]
+
+        if item := m.ItemById(id); nil != item && m.RevealItem(item) {
+            return true
+        }
+    }
+
+    m.status = "no previous position"
+
+    return false
+}
+
+// BrokenLinks returns all links whose target does not exist (anymore).
+func (m *model) BrokenLinks() []OItem {
+    var result []OItem
+
+    var walk func(item OItem)
+
+    walk = func(item OItem) {
+        if m.IsBrokenLink(item) {
+            result = append(result, item)
+        }
+
+        if !storesSubs(item) {
+            return
+        }
+
+        for _, sub := range item.GetSubs() {
+            walk(sub)
+        }
+    }
+
+    walk(m.Title)
+
+    return result
+}
+
+func (m *model) OpenBrokenLinkList() {
+    describe := func(item OItem) string {
+        return fmt.Sprintf("⚠ link to %s  %s", item.(*oitemlink).Target, m.itemLocation(item))
+    }
+
+    m.OpenItemList(fmt.Sprintf("Broken links in %s", m.filename), m.BrokenLinks(), describe, "no broken links")
+}
@@ -0,0 +1,89 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+func TestFollowLinkAndJumpBack(t *testing.T) {
+    m := InitialModel()
+    parent := m.Title.GetSubs()[0]
+    target := m.AddNewItem(parent)
+    target.SetTxt("target")
+    m.Collapse(parent)
+
+    link := NewLink(target)
+    m.AddSubAfterThis(parent, link)
+
+    if link.GetTxt() != "target" {
+        t.Error("Expected link to show the target's text, but got", link.GetTxt())
+    }
+
+    if !m.FollowLink(link) || m.linearized[m.Cursor] != target {
+        t.Fatal("Expected cursor on the target after following the link")
+    }
+
+    if !parent.IsExpanded() {
+        t.Error("Expected ancestors of the target to be expanded")
+    }
+
+    if !m.JumpBack() || m.linearized[m.Cursor] != link {
+        t.Error("Expected cursor on the link after jumping back")
+    }
+
+    if m.JumpBack() {
+        t.Error("Expected empty navigation history")
+    }
+}
+
+func TestBrokenLinks(t *testing.T) {
+    m := InitialModel()
+    first := m.Title.GetSubs()[0]
+    target := m.AddNewItem(m.Title)
+    link := NewLink(target)
+    m.AddSubAfterThis(first, link)
+
+    if len(m.BrokenLinks()) != 0 {
+        t.Fatal("Expected no broken links")
+    }
+
+    m.PushUndo()
+    m.DeleteItem(target)
+
+    if broken := m.BrokenLinks(); len(broken) != 1 || broken[0] != link {
+        t.Fatal("Expected the link to be broken after deleting its target, but got", broken)
+    }
+
+    if m.FollowLink(link) {
+        t.Error("Expected following a broken link to fail")
+    }
+
+    m.PopUndo()
+
+    if broken := m.BrokenLinks(); len(broken) != 0 {
+        t.Error("Expected undo to restore the target, but got", broken)
+    }
+}
+
+func TestLinkSurvivesSaveAndLoad(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    target.SetTxt("target")
+    m.AddSubAfterThis(target, NewLink(target))
+
+    b, err := json.Marshal(m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    loaded := loadFromJSON(t, b)
+    link := loaded.Title.GetSubs()[1]
+
+    if link.GetType() != OTypeProxyLink || loaded.LinkTarget(link) != loaded.Title.GetSubs()[0] {
+        t.Fatal("Expected link to the first item after loading, but got", link)
+    }
+
+    if link.GetTxt() != "target" {
+        t.Error("Expected link to show the target's text, but got", link.GetTxt())
+    }
+}
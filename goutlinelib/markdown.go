@@ -0,0 +1,355 @@
+package goutlinelib
+
+import(
+    "bytes"
+    "fmt"
+    "strings"
+    "unicode"
+)
+
+type MarkdownExportOptions struct {
+    // only export this item and its subs; the whole outline if nil
+    Root OItem
+
+    // do not export subs of collapsed items
+    OnlyExpanded bool
+}
+
+type markdownExportVisitor struct {
+    options MarkdownExportOptions
+
+    buf bytes.Buffer
+
+    // level of the items that are exported as top level list items
+    baseLevel int
+
+    // indentation of the contents of the most recent list item on each depth
+    contentIndent []int
+}
+
+func (v *markdownExportVisitor) VisitTitle(m *model, item OItem) error {
+    fmt.Fprintf(&v.buf, "# %s\n\n", item.GetTxt())
+
+    if "" != item.GetBody() {
+        v.buf.WriteString(strings.TrimRight(item.GetBody(), "\n") + "\n\n")
+    }
+
+    return nil
+}
+
+func (v *markdownExportVisitor) VisitConfig(m *model, item OItem) error {
+    return nil
+}
+
+func (v *markdownExportVisitor) VisitItem(m *model, item OItem, level int) error {
+    depth := level - v.baseLevel
+    indent := 0
+
+    if depth > 0 {
+        indent = v.contentIndent[depth - 1]
+    }
+
+    marker := "- "
+
+    // the position of an exported subtree's root within its parent is meaningless
+    if (depth > 0 || nil == v.options.Root) && nil != item.GetParent() && item.GetParent().IsNumbered() {
+        marker = fmt.Sprintf("%d. ", item.IndexOfItem() + 1)
+    }
+
+    v.contentIndent = append(v.contentIndent[:depth], indent + len(marker))
+
+    checkbox := ""
+
+    if item.IsChecked() {
+        checkbox = "[x] "
+    } else if IsTask(item) {
+        checkbox = "[ ] "
+    }
+
+    fmt.Fprintf(&v.buf, "%s%s%s%s%s\n", strings.Repeat(" ", indent), marker, checkbox, item.GetTxt(), markdownTags(item))
+
+    if "" != item.GetBody() {
+        body_indent := strings.Repeat(" ", indent + len(marker))
+
+        for _, line := range strings.Split(strings.TrimRight(item.GetBody(), "\n"), "\n") {
+            if "" == strings.TrimSpace(line) {
+                v.buf.WriteString("\n")
+            } else {
+                v.buf.WriteString(body_indent + line + "\n")
+            }
+        }
+    }
+
+    return nil
+}
+
+// markdownTags returns the tags of item that are not in its text already as
+// " #a #b", which are read back as tags; tags that would not be read back as
+// such are left out.
+func markdownTags(item OItem) string {
+    result := ""
+
+    for _, tag := range unwrittenTags(item) {
+        if isInlineTag("#" + tag) {
+            result += " #" + tag
+        }
+    }
+
+    return result
+}
+
+func (v *markdownExportVisitor) ShouldDescend(m *model, item OItem) bool {
+    return !v.options.OnlyExpanded || item.IsExpanded()
+}
+
+// MarshalMarkdownWithOptions exports the outline as nested bullet lists; subs
+// of numbered items become ordered lists and tasks get check boxes.
+func (m model) MarshalMarkdownWithOptions(options MarkdownExportOptions) ([]byte, error) {
+    v := &markdownExportVisitor{options: options, baseLevel: 1}
+
+    var err error
+
+    if nil == options.Root {
+        err = m.VisitAll(v)
+    } else {
+        v.baseLevel = options.Root.Level(nil)
+        err = m.VisitSubtree(v, options.Root)
+    }
+
+    if nil != err {
+        return nil, fmt.Errorf("Could not export Markdown: %w", err)
+    }
+
+    return v.buf.Bytes(), nil
+}
+
+func (m model) MarshalMarkdown() ([]byte, error) {
+    return m.MarshalMarkdownWithOptions(MarkdownExportOptions{})
+}
+
+func parseMarkdownHeading(line string) (level int, txt string, ok bool) {
+    for level < len(line) && line[level] == '#' {
+        level++
+    }
+
+    if 0 == level || level > 6 {
+        return
+    }
+
+    if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+        return
+    }
+
+    return level, strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#")), true
+}
+
+func parseMarkdownListItem(line string) (indent int, contentIndent int, ordered bool, txt string, ok bool) {
+    trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+    indent = len(line) - len(trimmed)
+
+    marker_len := 0
+
+    if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ ") {
+        marker_len = 2
+    } else {
+        digits := 0
+
+        for digits < len(trimmed) && trimmed[digits] >= '0' && trimmed[digits] <= '9' {
+            digits++
+        }
+
+        if digits > 0 && digits + 1 < len(trimmed) && (trimmed[digits] == '.' || trimmed[digits] == ')') && trimmed[digits + 1] == ' ' {
+            marker_len = digits + 2
+            ordered = true
+        }
+    }
+
+    if 0 == marker_len {
+        return
+    }
+
+    return indent, indent + marker_len, ordered, strings.TrimSpace(trimmed[marker_len:]), true
+}
+
+// markdownFencedLines tells for each line whether it belongs to a fenced code
+// block (``` or ~~~), including the fences themselves.
+func markdownFencedLines(lines []string) []bool {
+    result := make([]bool, len(lines))
+    fence := ""
+
+    for i, line := range lines {
+        trimmed := strings.TrimSpace(line)
+
+        if "" == fence {
+            for _, marker := range []string{"```", "~~~"} {
+                if strings.HasPrefix(trimmed, marker) {
+                    fence = marker
+                }
+            }
+        } else if strings.HasPrefix(trimmed, fence) && "" == strings.Trim(trimmed, fence[:1]) {
+            // closing fences have no info string
+            result[i] = true
+            fence = ""
+            continue
+        }
+
+        result[i] = "" != fence
+    }
+
+    return result
+}
+
+type markdownListEntry struct {
+    indent int
+    item OItem
+}
+
+type markdownHeadingEntry struct {
+    level int
+    item OItem
+}
+
+// UnmarshalMarkdown builds the outline from the structure of a Markdown
+// document: headings nest by level, list items by indentation below the
+// preceding heading. Other text ends up in the body of the preceding item. A
+// leading level 1 heading that is the only one of its level becomes the title.
+func (m *model) UnmarshalMarkdown(data []byte) (err error) {
+    title := &oitem{Type: "oitem"}
+
+    lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+    // lines of fenced code blocks are body text, even if they look like
+    // headings or list items
+    fenced := markdownFencedLines(lines)
+
+    top_headings := 0
+    first_content := -1
+
+    for i, line := range lines {
+        if level, _, ok := parseMarkdownHeading(line); ok && 1 == level && !fenced[i] {
+            top_headings++
+        }
+
+        if -1 == first_content && "" != strings.TrimSpace(line) {
+            first_content = i
+        }
+    }
+
+    var headings []markdownHeadingEntry
+    var list []markdownListEntry
+
+    var cur OItem = title
+    body_indent := 0
+    bodies := make(map[OItem][]string)
+
+    // whether all list items under a parent are ordered; mixing in a bullet
+    // list keeps the bullets
+    ordered_lists := make(map[OItem]bool)
+
+    parentForHeading := func(level int) OItem {
+        for len(headings) > 0 && headings[len(headings) - 1].level >= level {
+            headings = headings[:len(headings) - 1]
+        }
+
+        if len(headings) > 0 {
+            return headings[len(headings) - 1].item
+        }
+
+        return title
+    }
+
+    for i, line := range lines {
+        if level, txt, ok := parseMarkdownHeading(line); ok && !fenced[i] {
+            list = nil
+
+            if i == first_content && 1 == level && 1 == top_headings {
+                title.Txt = txt
+                continue
+            }
+
+            // trailing #tags stay part of the text as written
+            item := &oitem{Type: "oitem", Txt: txt}
+            _, tags := splitTrailingTags(txt)
+            SetItemTags(item, tags)
+            parent := parentForHeading(level)
+            parent.AddSubAt(item, len(parent.GetSubs()))
+            headings = append(headings, markdownHeadingEntry{level: level, item: item})
+
+            cur = item
+            body_indent = 0
+            continue
+        }
+
+        if indent, content_indent, ordered, txt, ok := parseMarkdownListItem(line); ok && !fenced[i] {
+            for len(list) > 0 && list[len(list) - 1].indent >= indent {
+                list = list[:len(list) - 1]
+            }
+
+            var parent OItem = title
+
+            if len(list) > 0 {
+                parent = list[len(list) - 1].item
+            } else if len(headings) > 0 {
+                parent = headings[len(headings) - 1].item
+            }
+
+            item := &oitem{Type: "oitem", Txt: txt}
+
+            if strings.HasPrefix(txt, "[ ] ") {
+                item.Txt = txt[4:]
+                SetMetaValue(item, metaTodo, "TODO")
+            } else if strings.HasPrefix(txt, "[x] ") || strings.HasPrefix(txt, "[X] ") {
+                item.Txt = txt[4:]
+                item.Checked = true
+                SetMetaValue(item, metaTodo, "DONE")
+            }
+
+            _, tags := splitTrailingTags(item.Txt)
+            SetItemTags(item, tags)
+
+            if all, seen := ordered_lists[parent]; !seen || all {
+                ordered_lists[parent] = ordered
+            }
+
+            parent.AddSubAt(item, len(parent.GetSubs()))
+            list = append(list, markdownListEntry{indent: indent, item: item})
+
+            cur = item
+            body_indent = content_indent
+            continue
+        }
+
+        // continuation text; strip the indentation of the list item it belongs to
+        trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+
+        if len(line) - len(trimmed) > body_indent {
+            trimmed = line[body_indent:]
+        }
+
+        bodies[cur] = append(bodies[cur], trimmed)
+    }
+
+    for parent, all := range ordered_lists {
+        if all {
+            parent.SetNumbered(true)
+        }
+    }
+
+    for item, body := range bodies {
+        txt := strings.Trim(strings.Join(body, "\n"), "\n")
+
+        if "" != txt {
+            item.SetBody(txt + "\n")
+        }
+    }
+
+    if !title.HasSubs() {
+        title.AddSubAt(&oitem{Type: "oitem"}, 0)
+    }
+
+    m.Title = title
+    m.Config = &oitem{}
+    m.Cursor = 0
+
+    return
+}
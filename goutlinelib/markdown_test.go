@@ -0,0 +1,197 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+const markdownRoundTripDoc = `# Shopping
+
+Things to get
+
+- Groceries
+  1. [x] Milk
+  2. [ ] Bread
+     whole grain
+  3. Apples
+- Hardware
+  - Screws
+`
+
+func TestMarkdownRoundTrip(t *testing.T) {
+    var m model
+
+    err := m.UnmarshalMarkdown([]byte(markdownRoundTripDoc))
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if m.Title.GetTxt() != "Shopping" {
+        t.Error("Expected", "Shopping", "for title, but got", m.Title.GetTxt())
+    }
+
+    groceries := m.Title.GetSubs()[0]
+
+    if !groceries.IsNumbered() || len(groceries.GetSubs()) != 3 {
+        t.Fatal("Unexpected groceries item", groceries)
+    }
+
+    if !groceries.GetSubs()[0].IsChecked() || !IsTask(groceries.GetSubs()[1]) || IsTask(groceries.GetSubs()[2]) {
+        t.Error("Unexpected task states", groceries.GetSubs())
+    }
+
+    res, err := m.MarshalMarkdown()
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if string(res) != markdownRoundTripDoc {
+        t.Error("Expected", markdownRoundTripDoc, "after round trip, but got", string(res))
+    }
+}
+
+func TestMarkdownImportHeadings(t *testing.T) {
+    doc := "# A\n\n## A.1\n\n- a\n  - b\n\n# B\n\ntext\n"
+
+    var m model
+    m.UnmarshalMarkdown([]byte(doc))
+
+    if len(m.Title.GetSubs()) != 2 {
+        t.Fatal("Expected", 2, "top level items, but got", len(m.Title.GetSubs()))
+    }
+
+    a1 := m.Title.GetSubs()[0].GetSubs()[0]
+
+    if a1.GetTxt() != "A.1" || a1.GetSubs()[0].GetSubs()[0].GetTxt() != "b" {
+        t.Error("Unexpected structure below A.1", a1)
+    }
+
+    if m.Title.GetSubs()[1].GetBody() != "text\n" {
+        t.Error("Expected", "text", "as body of B, but got", m.Title.GetSubs()[1].GetBody())
+    }
+}
+
+func TestMarkdownExportOptions(t *testing.T) {
+    var m model
+    m.UnmarshalMarkdown([]byte(markdownRoundTripDoc))
+
+    groceries := m.Title.GetSubs()[0]
+
+    res, _ := m.MarshalMarkdownWithOptions(MarkdownExportOptions{Root: groceries, OnlyExpanded: true})
+
+    if string(res) != "- Groceries\n" {
+        t.Error("Expected only the collapsed root, but got", string(res))
+    }
+
+    groceries.SetExpanded(true)
+    res, _ = m.MarshalMarkdownWithOptions(MarkdownExportOptions{Root: groceries, OnlyExpanded: true})
+
+    expected := "- Groceries\n  1. [x] Milk\n  2. [ ] Bread\n     whole grain\n  3. Apples\n"
+
+    if string(res) != expected {
+        t.Error("Expected", expected, "but got", string(res))
+    }
+}
+
+func TestMarkdownFencedCode(t *testing.T) {
+    doc := "# Notes\n\n- Setup\n  ```sh\n  # not a heading\n  - not an item\n\n  make\n  ```\n- Done\n"
+
+    var m model
+    m.UnmarshalMarkdown([]byte(doc))
+
+    subs := m.Title.GetSubs()
+
+    if m.Title.GetTxt() != "Notes" || len(subs) != 2 || len(subs[0].GetSubs()) != 0 {
+        t.Fatal("Expected code to stay in the body, but got", subs)
+    }
+
+    if !strings.Contains(subs[0].GetBody(), "# not a heading\n- not an item\n\nmake\n```") {
+        t.Error("Unexpected body", subs[0].GetBody())
+    }
+
+    res, _ := m.MarshalMarkdown()
+
+    if string(res) != doc {
+        t.Error("Expected", doc, "after round trip, but got", string(res))
+    }
+}
+
+func TestMarkdownChecklistRoundTrip(t *testing.T) {
+    doc := "# Trip\n\n- [ ] Passport\n- [x] Tickets\n- [ ] Charger\n"
+
+    var m model
+    m.UnmarshalMarkdown([]byte(doc))
+    m.CommonPostInit()
+
+    // unchecked items stay tasks, also after a round trip through JSON
+    m.ToggleChecked(m.Title.GetSubs()[1])
+
+    b, err := json.Marshal(m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    loaded := loadFromJSON(t, b)
+    loaded.ToggleChecked(loaded.Title.GetSubs()[2])
+
+    res, _ := loaded.MarshalMarkdown()
+    expected := "# Trip\n\n- [ ] Passport\n- [ ] Tickets\n- [x] Charger\n"
+
+    if string(res) != expected {
+        t.Error("Expected", expected, "after round trip, but got", string(res))
+    }
+}
+
+func TestMarkdownMixedLists(t *testing.T) {
+    doc := "# Lists\n\n- [ ] task one\n1. first\n2. second\n"
+
+    var m model
+    m.UnmarshalMarkdown([]byte(doc))
+
+    if m.Title.IsNumbered() {
+        t.Error("Expected a bullet list followed by an ordered list not to be numbered")
+    }
+
+    if res, _ := m.MarshalMarkdown(); !strings.Contains(string(res), "- [ ] task one\n") {
+        t.Error("Expected the bullets to be kept, but got", string(res))
+    }
+}
+
+func TestMarkdownExportToCurrentFile(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    filename := filepath.Join(dir, "list.md")
+
+    if err := ioutil.WriteFile(filename, []byte(markdownRoundTripDoc), 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m, err := ModelFromFile(filename)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m.SetReadOnly(true)
+    m.Export(ExportFilename(m.filename, ".md"))
+    m.SetReadOnly(false)
+    m.ExportMarkdown(filename, MarkdownExportOptions{Root: m.Title.GetSubs()[0]})
+
+    if b, _ := ioutil.ReadFile(filename); string(b) != markdownRoundTripDoc {
+        t.Error("Expected file to be unchanged, but got", string(b))
+    }
+
+    m.Export(ExportFilename(m.filename, ".md"))
+
+    if _, err := os.Stat(filename + ".bak"); err != nil {
+        t.Error("Expected exporting to the current file to save it with a backup, but got", err)
+    }
+}
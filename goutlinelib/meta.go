@@ -0,0 +1,252 @@
+package goutlinelib
+
+import(
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Properties of an item are stored as subs of its meta item, one sub per
+// property with a text of the form "key = value".
+
+type MetaProperty struct {
+    Key string
+    Value string
+}
+
+func parseMetaEntry(txt string) (key string, value string, ok bool) {
+    idx := strings.Index(txt, "=")
+
+    if -1 == idx {
+        return
+    }
+
+    key = strings.TrimSpace(txt[:idx])
+    value = strings.TrimSpace(txt[idx + 1:])
+    ok = "" != key
+
+    return
+}
+
+func formatMetaEntry(key string, value string) string {
+    return key + " = " + value
+}
+
+func findMetaEntry(item OItem, key string) OItem {
+    if nil == item || nil == item.GetMeta() {
+        return nil
+    }
+
+    for _, sub := range item.GetMeta().GetSubs() {
+        if cur_key, _, ok := parseMetaEntry(sub.GetTxt()); ok && strings.EqualFold(cur_key, key) {
+            return sub
+        }
+    }
+
+    return nil
+}
+
+// MetaProperties returns all properties of the given item in their stored order.
+func MetaProperties(item OItem) []MetaProperty {
+    var result []MetaProperty
+
+    if nil == item || nil == item.GetMeta() {
+        return result
+    }
+
+    for _, sub := range item.GetMeta().GetSubs() {
+        if key, value, ok := parseMetaEntry(sub.GetTxt()); ok {
+            result = append(result, MetaProperty{Key: key, Value: value})
+        }
+    }
+
+    return result
+}
+
+// MetaValue looks up a property of the given item; keys are case insensitive.
+func MetaValue(item OItem, key string) (string, bool) {
+    entry := findMetaEntry(item, key)
+
+    if nil == entry {
+        return "", false
+    }
+
+    _, value, _ := parseMetaEntry(entry.GetTxt())
+
+    return value, true
+}
+
+// MetaInt returns the value of an integer property, or fallback if it is
+// missing or not a number.
+func MetaInt(item OItem, key string, fallback int) int {
+    value, found := MetaValue(item, key)
+
+    if !found {
+        return fallback
+    }
+
+    result, err := strconv.Atoi(value)
+
+    if err != nil {
+        return fallback
+    }
+
+    return result
+}
+
+// MetaBool returns the value of a boolean property, or fallback if it is
+// missing or not a boolean.
+func MetaBool(item OItem, key string, fallback bool) bool {
+    if result, ok := MetaBoolValue(item, key); ok {
+        return result
+    }
+
+    return fallback
+}
+
+// layouts of date properties, in local time
+const (
+    metaDateLayout = "2006-01-02"
+    metaDateTimeLayout = "2006-01-02 15:04"
+)
+
+func parseMetaNumber(value string) (float64, bool) {
+    result, err := strconv.ParseFloat(value, 64)
+    return result, nil == err
+}
+
+func parseMetaDate(value string) (time.Time, bool) {
+    for _, layout := range []string{metaDateTimeLayout, metaDateLayout} {
+        if result, err := time.ParseInLocation(layout, value, time.Local); nil == err {
+            return result, true
+        }
+    }
+
+    return time.Time{}, false
+}
+
+// parseMetaBool accepts "true"/"false", "yes"/"no", "on"/"off" and "1"/"0".
+func parseMetaBool(value string) (bool, bool) {
+    switch strings.ToLower(value) {
+
+    case "true", "yes", "on", "1":
+        return true, true
+
+    case "false", "no", "off", "0":
+        return false, true
+    }
+
+    return false, false
+}
+
+// MetaNumberValue, MetaDateValue and MetaBoolValue are the typed variants of
+// MetaValue; ok is false if the property is missing or has a value of another
+// type.
+
+func MetaNumberValue(item OItem, key string) (float64, bool) {
+    value, found := MetaValue(item, key)
+
+    if !found {
+        return 0, false
+    }
+
+    return parseMetaNumber(value)
+}
+
+func MetaDateValue(item OItem, key string) (time.Time, bool) {
+    value, found := MetaValue(item, key)
+
+    if !found {
+        return time.Time{}, false
+    }
+
+    return parseMetaDate(value)
+}
+
+func MetaBoolValue(item OItem, key string) (bool, bool) {
+    value, found := MetaValue(item, key)
+
+    if !found {
+        return false, false
+    }
+
+    return parseMetaBool(value)
+}
+
+// MetaValueType names the type of a property value as understood by the
+// typed accessors.
+func MetaValueType(value string) string {
+    if _, ok := parseMetaNumber(value); ok {
+        return "number"
+    }
+
+    if _, ok := parseMetaDate(value); ok {
+        return "date"
+    }
+
+    if _, ok := parseMetaBool(value); ok {
+        return "bool"
+    }
+
+    return "text"
+}
+
+func SetMetaNumber(item OItem, key string, value float64) {
+    SetMetaValue(item, key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// SetMetaDate stores a date, with the time of day unless it is midnight.
+func SetMetaDate(item OItem, key string, value time.Time) {
+    value = value.Local()
+    layout := metaDateTimeLayout
+
+    if 0 == value.Hour() && 0 == value.Minute() {
+        layout = metaDateLayout
+    }
+
+    SetMetaValue(item, key, value.Format(layout))
+}
+
+func SetMetaBool(item OItem, key string, value bool) {
+    SetMetaValue(item, key, strconv.FormatBool(value))
+}
+
+// canHaveMeta tells whether properties can be stored for item; links only
+// refer to their target.
+func canHaveMeta(item OItem) bool {
+    _, isLink := item.(*oitemlink)
+    return !isLink
+}
+
+// SetMetaValue sets a property of item; it returns false if item cannot have
+// properties.
+func SetMetaValue(item OItem, key string, value string) bool {
+    if nil == item {
+        return false
+    }
+
+    if entry := findMetaEntry(item, key); nil != entry {
+        entry.SetTxt(formatMetaEntry(key, value))
+        return true
+    }
+
+    if nil == item.GetMeta() {
+        item.SetMeta(&oitem{Type: "oitem"})
+    }
+
+    meta := item.GetMeta()
+
+    if nil == meta {
+        return false
+    }
+
+    meta.AddSubAt(&oitem{Type: "oitem", Txt: formatMetaEntry(key, value)}, len(meta.GetSubs()))
+
+    return true
+}
+
+func DeleteMetaValue(item OItem, key string) {
+    if entry := findMetaEntry(item, key); nil != entry {
+        item.GetMeta().Delete(entry)
+    }
+}
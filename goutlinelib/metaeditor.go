@@ -0,0 +1,173 @@
+package goutlinelib
+
+import(
+    "fmt"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/charmbracelet/lipgloss"
+)
+
+// shown instead of the outline while editing the meta properties of an item
+type metaEditor struct {
+    item OItem
+    cursor int
+}
+
+// OpenMetaEditor shows the properties of item for editing.
+func (m *model) OpenMetaEditor(item OItem) {
+    if !canHaveMeta(item) {
+        m.status = "links have no properties"
+        return
+    }
+
+    m.metaEditor = &metaEditor{item: item}
+}
+
+func (m *model) metaEntries() []OItem {
+    if meta := m.metaEditor.item.GetMeta(); nil != meta {
+        return meta.GetSubs()
+    }
+
+    return nil
+}
+
+// changeMeta applies a change to the properties of the edited item, which
+// can be undone.
+func (m *model) changeMeta(change func(item OItem)) {
+    item := m.metaEditor.item
+
+    m.PushUndo()
+    change(item)
+    item.SetTimestampChangedNow()
+    m.MarkDirty()
+
+    // e.g. tags and the TODO property change how items are shown
+    m.UpdateLinearizedMapping()
+}
+
+// SetMetaEntry sets a property given as "key = value".
+func (m *model) SetMetaEntry(entry string) {
+    key, value, ok := parseMetaEntry(entry)
+
+    if !ok {
+        m.status = "expected key = value"
+        return
+    }
+
+    m.changeMeta(func(item OItem) {
+        SetMetaValue(item, key, value)
+    })
+}
+
+// EditMetaEntry replaces the property under the cursor with one given as
+// "key = value".
+func (m *model) EditMetaEntry(entry string) {
+    entries := m.metaEntries()
+    editor := m.metaEditor
+
+    if editor.cursor >= len(entries) {
+        return
+    }
+
+    key, value, ok := parseMetaEntry(entry)
+
+    if !ok {
+        m.status = "expected key = value"
+        return
+    }
+
+    m.changeMeta(func(item OItem) {
+        entries[editor.cursor].SetTxt(formatMetaEntry(key, value))
+    })
+}
+
+// DeleteMetaEntry deletes the property under the cursor.
+func (m *model) DeleteMetaEntry() {
+    entries := m.metaEntries()
+    editor := m.metaEditor
+
+    if editor.cursor >= len(entries) {
+        return
+    }
+
+    m.changeMeta(func(item OItem) {
+        item.GetMeta().Delete(entries[editor.cursor])
+    })
+
+    if editor.cursor > 0 && editor.cursor >= len(m.metaEntries()) {
+        editor.cursor--
+    }
+}
+
+func (m *model) handleMetaEditorKey(msg tea.KeyMsg) {
+    editor := m.metaEditor
+    entries := m.metaEntries()
+
+    m.status = ""
+
+    switch msg.String() {
+
+    case "up", "k":
+        if editor.cursor > 0 {
+            editor.cursor--
+        }
+
+    case "down", "j":
+        if editor.cursor < len(entries) - 1 {
+            editor.cursor++
+        }
+
+    case "a", "o":
+        m.OpenPrompt("new property: ", "", func(m *model, value string) {
+            m.SetMetaEntry(value)
+        })
+
+    case "enter", "i":
+        if editor.cursor < len(entries) {
+            m.OpenPrompt("property: ", entries[editor.cursor].GetTxt(), func(m *model, value string) {
+                m.EditMetaEntry(value)
+            })
+        }
+
+    case "d", "delete", "backspace":
+        m.DeleteMetaEntry()
+
+    case "esc", "q", "ctrl+c":
+        m.metaEditor = nil
+    }
+}
+
+func (m model) metaEditorView() string {
+    editor := m.metaEditor
+
+    type_style := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+    s := headerStyle.Render(fmt.Sprintf("Properties of %q", editor.item.GetTxt())) + "\n\n"
+
+    entries := m.metaEntries()
+
+    if 0 == len(entries) {
+        s += "  (none)\n"
+    }
+
+    for i, entry := range entries {
+        line := entry.GetTxt()
+        kind := ""
+
+        if _, value, ok := parseMetaEntry(line); ok {
+            kind = type_style.Render(" (" + MetaValueType(value) + ")")
+        }
+
+        s += listLine(line + kind, i == editor.cursor)
+    }
+
+    s += "\na: add   enter: edit   d: delete   esc: back\n"
+
+    if nil != m.prompt {
+        s += m.promptView()
+    } else if "" != m.status {
+        s += m.status + "\n"
+    }
+
+    return s
+}
@@ -0,0 +1,96 @@
+package goutlinelib
+
+import (
+    "testing"
+    "time"
+)
+
+func TestTypedMetaAccessors(t *testing.T) {
+    item := &oitem{Type: "oitem"}
+    SetMetaNumber(item, "estimate", 2.5)
+    SetMetaBool(item, "billable", true)
+    SetMetaDate(item, "due", time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local))
+    SetMetaDate(item, "meeting", time.Date(2026, 11, 2, 14, 30, 0, 0, time.Local))
+    SetMetaValue(item, "owner", "me")
+
+    if value, ok := MetaNumberValue(item, "estimate"); !ok || value != 2.5 {
+        t.Error("Unexpected number", value, ok)
+    }
+
+    if value, ok := MetaBoolValue(item, "Billable"); !ok || !value {
+        t.Error("Unexpected bool", value, ok)
+    }
+
+    if value, _ := MetaValue(item, "due"); value != "2026-11-01" {
+        t.Error("Expected date without time of day, but got", value)
+    }
+
+    if value, ok := MetaDateValue(item, "meeting"); !ok || value.Hour() != 14 || value.Minute() != 30 {
+        t.Error("Unexpected date", value, ok)
+    }
+
+    if _, ok := MetaNumberValue(item, "owner"); ok {
+        t.Error("Expected text not to be a number")
+    }
+
+    if _, ok := MetaDateValue(item, "missing"); ok {
+        t.Error("Expected missing property not to be found")
+    }
+
+    for value, expected := range map[string]string{"3": "number", "2026-11-01": "date", "off": "bool", "me": "text"} {
+        if kind := MetaValueType(value); kind != expected {
+            t.Error("Expected", expected, "for", value, "but got", kind)
+        }
+    }
+}
+
+func TestMetaEditor(t *testing.T) {
+    m := InitialModel()
+    item := m.Title.GetSubs()[0]
+
+    m.OpenMetaEditor(item)
+    m.SetMetaEntry("priority = 1")
+    m.SetMetaEntry("context=home")
+
+    if props := MetaProperties(item); len(props) != 2 || props[1] != (MetaProperty{Key: "context", Value: "home"}) {
+        t.Fatal("Expected added properties, but got", props)
+    }
+
+    m.SetMetaEntry("not a property")
+
+    if "" == m.status || len(MetaProperties(item)) != 2 {
+        t.Error("Expected invalid entry to be rejected")
+    }
+
+    m.metaEditor.cursor = 1
+    m.EditMetaEntry("context = work")
+    m.DeleteMetaEntry()
+
+    if props := MetaProperties(item); len(props) != 1 || m.metaEditor.cursor != 0 {
+        t.Error("Expected property to be deleted, but got", props)
+    }
+
+    m.PopUndo()
+
+    if value, _ := MetaValue(m.Title.GetSubs()[0], "context"); value != "work" {
+        t.Error("Expected deletion to be undone, but got", value)
+    }
+
+    m.PopUndo()
+
+    if value, _ := MetaValue(m.Title.GetSubs()[0], "context"); value != "home" {
+        t.Error("Expected edit to be undone, but got", value)
+    }
+}
+
+func TestSetMetaValueOnLink(t *testing.T) {
+    link := NewLink(&oitem{Type: "oitem", Id: "target"})
+
+    if SetMetaValue(link, "key", "value") {
+        t.Error("Expected links not to store properties")
+    }
+
+    if _, found := MetaValue(link, "key"); found {
+        t.Error("Expected no property on the link")
+    }
+}
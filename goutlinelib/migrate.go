@@ -0,0 +1,92 @@
+package goutlinelib
+
+import(
+    "time"
+)
+
+// FormatVersion is the version of the JSON layout written when saving; files
+// without a version are version 1.
+const FormatVersion = 2
+
+// A migration upgrades the generic JSON representation of a file by one
+// version before it is converted into items.
+type migration struct {
+    // version the migration upgrades from
+    from int
+
+    // shown in the status after loading an upgraded file
+    description string
+
+    apply func(temp map[string]interface{})
+}
+
+// to introduce a new version, add a migration from the current version and
+// increase FormatVersion; missing ids need no migration, as they are assigned
+// to items from any source when loading
+var migrations = []migration{
+    {from: 1, description: "timestamps as seconds", apply: migrateTimestamps},
+}
+
+// migrateTimestamps converts the timestamps that early versions wrote as
+// RFC 3339 strings into seconds since the epoch.
+func migrateTimestamps(temp map[string]interface{}) {
+    for _, key := range []string{"Title", "Config"} {
+        if item, ok := temp[key].(map[string]interface{}); ok {
+            migrateItemTimestamps(item)
+        }
+    }
+}
+
+func migrateItemTimestamps(item map[string]interface{}) {
+    for _, key := range []string{"Created", "Changed"} {
+        if value, ok := item[key].(string); ok {
+            // anything else is reported as a problem when loading
+            if parsed, err := time.Parse(time.RFC3339, value); nil == err {
+                item[key] = float64(parsed.Unix())
+            }
+        }
+    }
+
+    subs, _ := item["Subs"].([]interface{})
+
+    for _, sub := range subs {
+        if sub, ok := sub.(map[string]interface{}); ok {
+            migrateItemTimestamps(sub)
+        }
+    }
+}
+
+// migrate upgrades the representation to FormatVersion and returns the
+// version it had before, and the migrations applied; files from newer
+// versions are left as they are.
+func migrate(l *jsonLoader, temp map[string]interface{}) (version int, applied []migration) {
+    version = 1
+
+    if value, found := temp["Version"]; found {
+        number, ok := value.(float64)
+
+        if !ok {
+            l.problem("Version", "expected number, got %s", jsonTypeName(value))
+            return
+        }
+
+        version = int(number)
+    }
+
+    current := version
+
+    for _, cur := range migrations {
+        if cur.from == current {
+            if nil != cur.apply {
+                cur.apply(temp)
+            }
+
+            applied = append(applied, cur)
+            current++
+        }
+    }
+
+    temp["Version"] = float64(current)
+
+    return
+}
@@ -0,0 +1,88 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestMigrateLegacyFile(t *testing.T) {
+    var m model
+
+    err := json.Unmarshal([]byte(`{"Title": {"Txt": "t", "Subs": [{"Txt": "a", "Created": "2020-01-02T03:04:05Z", "Subs": [{"Txt": "b", "Changed": 1577934245}]}]}, "Cursor": 0}`), &m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if m.readOnly || len(m.loadProblems) != 0 {
+        t.Error("Expected migrated file to be writable and without problems, but got", m.loadProblems)
+    }
+
+    if !strings.Contains(m.status, "timestamps as seconds") {
+        t.Error("Expected status to tell what was upgraded, but got", m.status)
+    }
+
+    m.CommonPostInit()
+
+    a := m.Title.GetSubs()[0]
+
+    if a.GetCreated() != 1577934245 || a.GetSubs()[0].GetChanged() != 1577934245 {
+        t.Error("Expected timestamps in seconds after migration, but got", a.GetCreated(), a.GetSubs()[0].GetChanged())
+    }
+
+    if "" == m.Title.GetId() || "" == a.GetId() || "" == a.GetSubs()[0].GetId() || a.GetId() == a.GetSubs()[0].GetId() {
+        t.Error("Expected unique ids after loading, but got", m.Title.GetId(), a.GetId(), a.GetSubs()[0].GetId())
+    }
+
+    b, _ := json.Marshal(m)
+
+    var written map[string]interface{}
+    json.Unmarshal(b, &written)
+
+    if written["Version"] != float64(FormatVersion) {
+        t.Error("Expected", FormatVersion, "as written version, but got", written["Version"])
+    }
+}
+
+func TestNewerVersionIsReadOnly(t *testing.T) {
+    var m model
+
+    err := json.Unmarshal([]byte(`{"Version": 999, "Title": {"Txt": "t", "Subs": [{"Txt": "a", "Future": 1}]}}`), &m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if !m.readOnly || len(m.loadProblems) != 1 || m.loadProblems[0].Path != "Version" {
+        t.Error("Expected read-only model with version problem, but got", m.loadProblems)
+    }
+}
+
+func TestNewerVersionIsNeverSaved(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    filename := filepath.Join(dir, "future.json")
+    contents := []byte(`{"Version": 999, "Title": {"Txt": "t", "Subs": [{"Txt": "a", "Future": 1}]}}`)
+
+    if err := ioutil.WriteFile(filename, contents, 0644); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    m, _ := ModelFromFile(filename)
+    m.SetReadOnly(false)
+
+    if m.Save() || !strings.Contains(m.status, "newer version") {
+        t.Error("Expected saving to be refused, but got status", m.status)
+    }
+
+    if b, _ := ioutil.ReadFile(filename); string(b) != string(contents) {
+        t.Error("Expected file to be unchanged, but got", string(b))
+    }
+}
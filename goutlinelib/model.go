@@ -5,6 +5,8 @@ import(
     "fmt"
     "io/ioutil"
     "encoding/json"
+    "strings"
+    "time"
 
     tea "github.com/charmbracelet/bubbletea"
     "github.com/charmbracelet/lipgloss"
@@ -17,6 +19,9 @@ const verticalMarginHeight = 0
 const headerHeight = 0
 
 type model struct {
+
+    // version of the file format, see FormatVersion
+    Version int
     
     Title OItem
     
@@ -44,6 +49,83 @@ type model struct {
     currentStateReachedViaUndoList bool
 
     newestItem OItem
+
+    // feedback for the last command, shown in the footer
+    status string
+
+    backups []Backup
+    backupCursor int
+    choosingBackup bool
+
+    // modified since the last successful save
+    dirty bool
+
+    // incremented by every modification; used to detect idle periods
+    changeCount int
+
+    // newer recovery journal found when loading, waiting for a decision
+    pendingJournal string
+
+    // quit was requested while there are unsaved changes
+    confirmingQuit bool
+
+    // recoverable problems found when loading
+    loadProblems []LoadProblem
+
+    // line of the org-mode file (counting from 1) that is its "#+TITLE:" line,
+    // even if it is empty; 0 if there is none
+    orgTitleLine int
+
+    // saving is refused, e.g. to not overwrite a damaged file with its repaired version
+    readOnly bool
+
+    // format version of a file written by a newer version of goutline; such
+    // files are never saved over, not even after W, as unknown fields would
+    // get lost
+    newerVersion int
+
+    // all items by their id; kept up to date by the operations that add or remove items
+    itemsById map[string]OItem
+
+    // transclusions and links by the id of the item they refer to; kept up to date like itemsById
+    backlinks map[string][]OItem
+
+    // ids of the items jumped away from, most recent last
+    navHistory []string
+
+    // shown instead of the outline while choosing an item to jump to
+    itemList *itemList
+
+    // shown instead of the outline while choosing a tag to filter by
+    tagList *tagList
+
+    // shown instead of the outline while editing properties
+    metaEditor *metaEditor
+
+    // shown instead of the outline while viewing subs as a table
+    table *tableView
+
+    // shown instead of the outline while choosing a dated item
+    agenda *agenda
+
+    // input requested in the footer
+    prompt *prompt
+
+    // most recent search, highlighted until cleared with esc
+    search *search
+
+    // only matching items and their ancestors are shown if set
+    filter Filter
+    filterExpr string
+
+    // items added while filtering; shown although they might not match
+    filterKeep map[OItem]bool
+
+    // only the subs of this item are shown if set
+    hoisted OItem
+
+    // labels to jump to an item by typing them
+    jump *jumpMode
 }
 
 type Visitor interface {
@@ -54,16 +136,29 @@ type Visitor interface {
 }
 
 func (m *model) CommonPostInit() {
+    m.Version = FormatVersion
     m.undoIndex = -1
     m.redoIndex = -1
     
     m.Title.SetExpanded(true)
 
+    if nil == m.Config {
+        m.Config = &oitem{Type: "oitem"}
+    }
+
     for _, item := range m.Title.GetSubs() {
         item.SetParent(m.Title)
         item.Init()
     }
 
+    assignMissingIds(m.Title)
+
+    // only possible now that all potential targets have been loaded
+    resolveProxies(m.Title)
+
+    m.RebuildIdIndex()
+    m.restoreHoisted()
+
     m.UpdateLinearizedMapping()
 
     ti := textinput.New()
@@ -108,39 +203,68 @@ func (m *model) UnmarshalJSON(data []byte) (err error) {
         return
     }
 
-    if nil != temp["Title"] {
-        var tmpTitle OItem
-        tmpTitle, err = UnmarshalJSONOItem(temp["Title"].(map[string]interface{}))
+    l := &jsonLoader{}
 
-        if nil != err {
-            err = fmt.Errorf("Could not unmarshal title: %w", err)
-            return
+    version, applied := migrate(l, temp)
+
+    if version > FormatVersion {
+        // fields unknown to this version would get lost when saving
+        l.problem("Version", "file format %d is newer than the supported version %d", version, FormatVersion)
+        m.newerVersion = version
+        m.readOnly = true
+    } else if len(applied) > 0 {
+        var changes []string
+
+        for _, cur := range applied {
+            changes = append(changes, cur.description)
         }
 
-        m.Title = tmpTitle
-    } else {
+        m.status = fmt.Sprintf("upgraded file format from version %d to %d (%s)", version, FormatVersion, strings.Join(changes, ", "))
+    }
+
+    if nil == temp["Title"] {
         err = errors.New("No Title attribute")
         return
     }
 
-    if nil != temp["Config"] {
-        var tmpConfig OItem
-        tmpConfig, err = UnmarshalJSONOItem(temp["Config"].(map[string]interface{}))
+    m.Title = l.item(temp["Title"], "Title")
 
-        if nil != err {
-            err = fmt.Errorf("Could not unmarshal config: %w", err)
-            return
-        }
+    if nil == m.Title {
+        err = fmt.Errorf("Could not unmarshal title: %s", l.problems[len(l.problems) - 1])
+        return
+    }
+
+    if !m.Title.HasSubs() {
+        l.problem("Title.Subs", "no items")
+        m.Title.SetSubs([]OItem{&oitem{Type: "oitem"}})
+    }
 
-        m.Config = tmpConfig
+    if nil != temp["Config"] {
+        m.Config = l.item(temp["Config"], "Config")
     }
 
-    m.Cursor = int(temp["Cursor"].(float64))
+    m.Cursor = int(l.getInt64(temp, "", "Cursor"))
+
+    m.loadProblems = l.problems
 
     return
 }
 
+// ModelFromFile loads a file in the format given by its extension. If the
+// returned error is a *LoadError, the model is usable, but was repaired.
 func ModelFromFile(filename string) (model, error) {
+    result, err := modelFromFileAs(filename, FormatForFilename(filename))
+
+    var loadErr *LoadError
+
+    if nil == err || errors.As(err, &loadErr) {
+        result.detectJournal()
+    }
+
+    return result, err
+}
+
+func modelFromFileAs(filename string, format FileFormat) (model, error) {
     b, err := ioutil.ReadFile(filename)
 
     var result model
@@ -150,22 +274,56 @@ func ModelFromFile(filename string) (model, error) {
         return result, err
     }
 
-    err = json.Unmarshal(b, &result)
+    err = result.unmarshalFormat(format, b)
 
     if err != nil {
         err = fmt.Errorf("Could not unmarshal contents from %s: %w", filename, err)
         return result, err
     }
 
+    // relative sources of external items are resolved against the file's directory
+    result.filename = filename
+
     result.CommonPostInit()
 
-    result.filename = filename
+    if result.Cursor < 0 || result.Cursor >= result.linearCount {
+        result.loadProblems = append(result.loadProblems, LoadProblem{Path: "Cursor", Message: fmt.Sprintf("%d is out of range", result.Cursor)})
+        result.Cursor = 0
+    }
+
+    if len(result.loadProblems) > 0 {
+        err = &LoadError{Filename: filename, Problems: result.loadProblems}
+    }
 
     return result, err
 }
 
+// SetReadOnly prevents (or allows again) saving to the current file.
+func (m *model) SetReadOnly(readOnly bool) {
+    m.readOnly = readOnly
+}
+
+func (m *model) IsReadOnly() bool {
+    return m.readOnly
+}
+
+// IsNewerVersion tells whether the file was written by a newer version of
+// goutline, so that it can only be exported under a new name.
+func (m *model) IsNewerVersion() bool {
+    return 0 != m.newerVersion
+}
+
+func (m *model) newerVersionStatus() string {
+    return fmt.Sprintf("%s was written by a newer version of goutline (file format %d); export it under a new name to keep your changes", m.filename, m.newerVersion)
+}
+
 func (m *model) SetFilename(filename string) {
     m.filename = filename
+    m.detectJournal()
+
+    // relative sources of external items are resolved against the file's directory
+    m.RebuildIdIndex()
+    m.UpdateLinearizedMapping()
 }
 
 func (m *model) PushUndo() {
@@ -211,8 +369,13 @@ func (m *model) PopUndo() {
     m.Title = m.undoList[m.undoIndex]
     m.undoIndex--
 
+    resolveProxies(m.Title)
+    m.RebuildIdIndex()
+    m.followFilterKeep()
+
     m.currentStateReachedViaUndoList = true
     m.UpdateLinearizedMapping()
+    m.MarkDirty()
 }
 
 func (m *model) Redo() {
@@ -225,17 +388,22 @@ func (m *model) Redo() {
     }
 
     m.Title = m.undoList[m.redoIndex]
+    resolveProxies(m.Title)
+    m.RebuildIdIndex()
+    m.followFilterKeep()
 
     m.undoIndex++
     m.redoIndex++
 
     m.currentStateReachedViaUndoList = true
     m.UpdateLinearizedMapping()
+    m.MarkDirty()
 }
 
 func (m *model) SetTitle(title string) {
     m.Title.SetTxt(title)
     m.Title.SetTimestampChangedNow()
+    m.MarkDirty()
 }
 
 func (m model) Init() tea.Cmd {
@@ -269,6 +437,12 @@ func (m *model) VisitAll(visitor Visitor) error {
     return nil
 }
 
+// VisitSubtree visits the given item and (depending on the visitor) its subs,
+// but neither title nor config.
+func (m *model) VisitSubtree(visitor Visitor, item OItem) error {
+    return m.visitItemInternal(visitor, item)
+}
+
 func (m *model) visitItemInternal(visitor Visitor, item OItem) error {
     err := visitor.VisitItem(m, item, item.Level(nil))
 
@@ -310,6 +484,11 @@ func (m *model) visitItemInternalOnlyExpanded(visitor Visitor, item OItem) error
 }
 
 type LinearizationVisitor struct {
+    // only these items are linearized if set, no matter whether expanded
+    visible map[OItem]bool
+
+    // checked items and their subs are not linearized
+    hideChecked bool
 }
 
 func (v *LinearizationVisitor) VisitTitle(m *model, item OItem) error {
@@ -321,6 +500,14 @@ func (v *LinearizationVisitor) VisitConfig(m *model, item OItem) error {
 }
 
 func (v *LinearizationVisitor) VisitItem(m *model, item OItem, level int) error {
+    if nil != v.visible && !v.visible[item] {
+        return nil
+    }
+
+    if v.hideChecked && item.IsChecked() {
+        return nil
+    }
+
     m.linearCount++
     m.linearized = append(m.linearized, item)
 
@@ -328,25 +515,76 @@ func (v *LinearizationVisitor) VisitItem(m *model, item OItem, level int) error
 }
 
 func (v *LinearizationVisitor) ShouldDescend(m *model, item OItem) bool {
+    if v.hideChecked && item.IsChecked() {
+        return false
+    }
+
+    if nil != v.visible {
+        return v.visible[item]
+    }
+
     return item.IsExpanded()
 }
 
 func (m *model) UpdateLinearizedMapping() {
+    m.updateHoisted()
+
+    v := &LinearizationVisitor{hideChecked: completedHide == m.CompletedMode()}
+
+    if nil != m.filter {
+        v.visible = m.filterVisible(m.filter)
+
+        // e.g. the last matching item has been deleted
+        if 0 == len(v.visible) {
+            m.filter = nil
+            m.filterExpr = ""
+            m.status = "no items match the filter anymore; showing all items"
+            v.visible = nil
+        }
+    }
+
+    m.linearizeView(v)
+
+    if 0 == m.linearCount && nil != m.hoisted {
+        m.status = "nothing to show below the hoisted item; showing all items"
+        m.setHoisted(nil)
+        m.linearizeView(v)
+    }
+
+    if 0 == m.linearCount && v.hideChecked {
+        m.status = "all items are checked; showing them anyway"
+        v.hideChecked = false
+        m.linearizeView(v)
+    }
+}
+
+// linearizeView linearizes the whole outline, or only the subs of the hoisted
+// item.
+func (m *model) linearizeView(v *LinearizationVisitor) {
     m.linearCount = 0
     m.linearized = nil
 
-    v := &LinearizationVisitor{}
-    m.VisitAll(v)
+    if nil == m.hoisted {
+        m.VisitAll(v)
+        return
+    }
+
+    for _, sub := range m.hoisted.GetSubs() {
+        m.visitItemInternal(v, sub)
+    }
 }
 
 func (m *model) AddNewItem(parent OItem) OItem {
-    new_item := &oitem{Type: "oitem", parent: parent, Txt: "", }
+    new_item := &oitem{Type: "oitem", Id: NewId(), parent: parent, Txt: "", }
     new_item.SetTimestampCreatedNow()
     parent.SetSubs(append(parent.GetSubs(), new_item))
+    m.indexSubtree(new_item)
+    m.keepVisible(new_item)
     //new_item.SetTxt(fmt.Sprintf("new %s.%d", parent.GetTxt(), len(parent.GetSubs()) - 1))
     m.UpdateLinearizedMapping()
 
     parent.SetTimestampChangedNow()
+    m.MarkDirty()
     return new_item
 }
 
@@ -369,8 +607,10 @@ func (m *model) AddNewItemAfterCurrentAndEdit(item OItem) OItem {
     insert_pos := item.IndexOfItem() + 1
 
     if -1 != insert_pos {
-        new_item := &oitem{parent: item.GetParent()}
+        new_item := &oitem{Type: "oitem", Id: NewId(), parent: item.GetParent()}
         new_item.SetTimestampCreatedNow()
+        m.indexSubtree(new_item)
+        m.keepVisible(new_item)
         item.GetParent().SetSubs(append(item.GetParent().GetSubs(), &oitem{}))
         copy(item.GetParent().GetSubs()[insert_pos + 1:], item.GetParent().GetSubs()[insert_pos:])
         item.GetParent().GetSubs()[insert_pos] = new_item
@@ -389,6 +629,7 @@ func (m *model) AddNewItemAfterCurrentAndEdit(item OItem) OItem {
         item.SetTimestampChangedNow()
 
         m.UpdateLinearizedMapping()
+        m.MarkDirty()
 
         return new_item
     }
@@ -396,6 +637,26 @@ func (m *model) AddNewItemAfterCurrentAndEdit(item OItem) OItem {
     return nil
 }
 
+// InsertReference inserts a transclusion or link (made by newRef) to the item
+// copied with c after item. The copied item is looked up by its id, as undo
+// and redo replace the items by copies.
+func (m *model) InsertReference(item OItem, newRef func(target OItem) OItem) {
+    if nil == m.refItem {
+        return
+    }
+
+    target := m.ItemById(m.refItem.GetId())
+    m.refItem = nil
+
+    if nil == target {
+        m.status = "the copied item is not part of the outline anymore"
+        return
+    }
+
+    m.PushUndo()
+    m.AddSubAfterThis(item, newRef(target))
+}
+
 func (m *model) PosInLinearized(item OItem) int {
     result := -1
 
@@ -445,6 +706,7 @@ func (m *model) DeleteItem(item OItem) OItem {
         item.SetTxt("empty")
 
         for _, sub := range item.GetSubs() {
+            m.unindexSubtree(sub)
             sub.SetParent(nil)
         }
 
@@ -452,10 +714,12 @@ func (m *model) DeleteItem(item OItem) OItem {
         item.SetTimestampChangedNow()
     } else {
         item.GetParent().Delete(item)
+        m.unindexSubtree(item)
     }
 
     p.SetTimestampChangedNow()
     m.UpdateLinearizedMapping()
+    m.MarkDirty()
 
     return item
 }
@@ -470,8 +734,12 @@ func (m *model) AddSubAfterThis(o OItem, item OItem) {
     }
 
     o.AddSubAfterThis(item)
+    m.renewDuplicateIds(item)
+    m.indexSubtree(item)
+    m.keepVisible(item)
 
     m.UpdateLinearizedMapping()
+    m.MarkDirty()
 
     if pos := m.PosInLinearized(item); -1 != pos {
         m.Cursor = pos
@@ -495,6 +763,8 @@ func (m *model) MoveUp(item OItem) bool {
         item.GetParent().GetSubs()[idx - 1] = item
         item.GetParent().GetSubs()[idx    ] = tmp
         // parent references can stay the same
+
+        m.MarkDirty()
     }
 
     m.UpdateLinearizedMapping()
@@ -529,6 +799,8 @@ func (m *model) MoveDown(item OItem) bool {
         item.GetParent().GetSubs()[idx + 1] = item
         item.GetParent().GetSubs()[idx    ] = tmp
         // parent references can stay the same
+
+        m.MarkDirty()
     }
 
     m.UpdateLinearizedMapping()
@@ -559,8 +831,18 @@ func (m *model) GoUp() {
 }
 
 func (m *model) ToggleChecked(item OItem) {
+    if !canBeTask(item) {
+        m.status = notTaskStatus(item, "checked")
+        return
+    }
+
     m.PushUndo()
-    item.SetChecked(!item.IsChecked())
+    setChecked(item, !item.IsChecked())
+    m.autoCheckParents(item)
+    m.MarkDirty()
+
+    // the item might be hidden now
+    m.updateLinearizedKeepingCursor()
 }
 
 func (m *model) Promote(item OItem) {
@@ -594,7 +876,7 @@ func (m *model) Promote(item OItem) {
             prec = cur
         }
 
-        if (nil != prec) && (-1 != index_of_item_within_parent) {
+        if (nil != prec) && (-1 != index_of_item_within_parent) && canHaveSubs(prec) {
             // remove item from parent's subs
             // trick from https://github.com/golang/go/wiki/SliceTricks
             item.GetParent().SetSubs(append(item.GetParent().GetSubs()[:index_of_item_within_parent], item.GetParent().GetSubs()[index_of_item_within_parent + 1 :]...))
@@ -608,6 +890,7 @@ func (m *model) Promote(item OItem) {
 
             m.Expand(item.GetParent())
             m.UpdateLinearizedMapping()
+            m.MarkDirty()
         }
     }
 }
@@ -631,7 +914,8 @@ func (m *model) Demote(item OItem) {
     // >  ├─ · new TODO.1 <
     //    └─ · new TODO.2
     //
-    if (nil != item.GetParent()) && (nil != item.GetParent().GetParent()) {
+    // items cannot leave the hoisted item
+    if (nil != item.GetParent()) && (nil != item.GetParent().GetParent()) && (item.GetParent() != m.hoisted) {
         index_of_item_within_parent := item.IndexOfItem()
         index_of_parent_within_its_parent := item.GetParent().IndexOfItem()
 
@@ -655,26 +939,81 @@ func (m *model) Demote(item OItem) {
             item.GetParent().SetTimestampChangedNow()
             item.SetTimestampChangedNow()
             m.UpdateLinearizedMapping()
+            m.MarkDirty()
         }
     }
 }
 
-func (m model) SaveCurrentAs(filename string) bool {
-    b, err := json.MarshalIndent(m, "", "    ")
+func (m model) SaveCurrentAs(filename string) error {
+    return m.saveAs(filename, m.BackupGenerations())
+}
+
+func (m model) saveAs(filename string, backupGenerations int) error {
+    b, err := m.marshalFormat(FormatForFilename(filename))
 
     if err != nil {
-        fmt.Printf("Error when marshalling struct for %s", filename)
-        return false
+        return err
     }
 
-    err = ioutil.WriteFile(filename, b, 0644)
+    return WriteFileAtomic(filename, b, backupGenerations)
+}
 
-    if err != nil {
-        fmt.Printf("Error when saving %s", filename)
-        return false
+// isCurrentFile tells whether filename names the file the outline is saved to.
+func (m *model) isCurrentFile(filename string) bool {
+    return absPath(filename) == absPath(m.filename)
+}
+
+// Export saves the outline in the format given by the extension of filename;
+// exporting to the current file is saving it.
+func (m *model) Export(filename string) {
+    if m.isCurrentFile(filename) {
+        // otherwise, Save has told why
+        if m.Save() {
+            m.status = fmt.Sprintf("saved %s", filename)
+        }
+
+        return
     }
 
-    return true
+    if err := m.saveAs(filename, 0); nil == err {
+        m.status = fmt.Sprintf("exported to %s", filename)
+    } else {
+        m.status = fmt.Sprintf("could not export to %s: %v", filename, err)
+    }
+}
+
+func (m *model) writeExport(filename string, b []byte, err error) {
+    if nil == err {
+        err = WriteFileAtomic(filename, b, 0)
+    }
+
+    if nil == err {
+        m.status = fmt.Sprintf("exported to %s", filename)
+    } else {
+        m.status = fmt.Sprintf("could not export to %s: %v", filename, err)
+    }
+}
+
+// ExportMarkdown exports the outline, or the part of it given by options, as
+// Markdown; a part is never written over the current file.
+func (m *model) ExportMarkdown(filename string, options MarkdownExportOptions) {
+    if m.isCurrentFile(filename) {
+        if nil == options.Root && !options.OnlyExpanded {
+            m.Export(filename)
+        } else {
+            m.status = fmt.Sprintf("not exporting a part of the outline over %s", filename)
+        }
+
+        return
+    }
+
+    b, err := m.MarshalMarkdownWithOptions(options)
+    m.writeExport(filename, b, err)
+}
+
+func (m *model) ExportHTML(filename string) {
+    b, err := m.MarshalHTML()
+    m.writeExport(filename, b, err)
 }
 
 func (m *model) handleWinSizeChange(msg tea.WindowSizeMsg) []tea.Cmd {
@@ -708,7 +1047,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
     canUpdateViewport := true
 
-    if m.editingItem {
+    changeCountBefore := m.changeCount
+
+    switch msg := msg.(type) {
+
+    case autosaveMsg:
+        m.handleAutosave(msg)
+
+    case journalMsg:
+        cmds = append(cmds, m.handleJournal(msg))
+
+    case journalWrittenMsg:
+        m.handleJournalWritten(msg)
+
+    case commandOutputMsg:
+        cmds = append(cmds, m.handleCommandOutput(msg))
+
+    case commandRefreshMsg:
+        m.handleCommandRefresh(msg)
+    }
+
+    if nil != m.prompt {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        default:
+            m.handlePromptKey(msg)
+            canUpdateViewport = false
+        }
+    } else if "" != m.pendingJournal {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleJournalKey(msg)
+            canUpdateViewport = false
+        }
+    } else if m.editingItem {
         switch msg := msg.(type) {
 
         case tea.WindowSizeMsg:
@@ -740,6 +1119,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                     m.PushUndo()
                     cur.SetTxt(new_text)
                     cur.SetTimestampChangedNow()
+                    m.MarkDirty()
                 }
             }
         }
@@ -747,6 +1127,88 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
         if m.editingItem {
             m.textinput, _ = m.textinput.Update(msg)
         }
+    } else if m.confirmingQuit {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            if cmd := m.handleQuitKey(msg); nil != cmd {
+                return m, cmd
+            }
+
+            canUpdateViewport = false
+        }
+    } else if m.choosingBackup {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleBackupListKey(msg)
+            canUpdateViewport = false
+        }
+    } else if nil != m.itemList {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleItemListKey(msg)
+            canUpdateViewport = false
+        }
+    } else if nil != m.tagList {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleTagListKey(msg)
+            canUpdateViewport = false
+        }
+    } else if nil != m.metaEditor {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleMetaEditorKey(msg)
+            canUpdateViewport = false
+        }
+    } else if nil != m.table {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            canUpdateViewport = m.handleTableKey(msg)
+        }
+    } else if nil != m.agenda {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleAgendaKey(msg)
+            canUpdateViewport = false
+        }
+    } else if nil != m.jump {
+        switch msg := msg.(type) {
+
+        case tea.WindowSizeMsg:
+            cmds = m.handleWinSizeChange(msg)
+
+        case tea.KeyMsg:
+            m.handleJumpKey(msg)
+            canUpdateViewport = false
+        }
     } else {
         switch msg := msg.(type) {
 
@@ -754,8 +1216,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
             cmds = m.handleWinSizeChange(msg)
 
         case tea.KeyMsg:
+            m.status = ""
 
-            switch msg.String() {
+            key := msg.String()
+
+            if modifyingKeys[key] && m.IsGenerated(cur) {
+                m.status = "generated from an external source (r: refresh)"
+                key = ""
+            }
+
+            switch key {
 
             case "i":
                 cur.SetEdited(true)
@@ -764,7 +1234,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 m.textinput.CursorEnd()
 
             case "c":
-                m.refItem = cur
+                // generated items cannot be referred to, but copied
+                if !m.IsGenerated(cur) {
+                    m.refItem = cur
+                }
+
                 m.copiedItem = cur.DeepCopy()
 
             case "x":
@@ -777,11 +1251,95 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
             // TODO: include as transcluded item
             case "t":
-                if nil != m.refItem {
-                    m.PushUndo()
-                    m.AddSubAfterThis(cur, NewProxy(m.refItem))
-                    m.refItem = nil
-                }
+                m.InsertReference(cur, NewProxy)
+
+            case "L":
+                m.InsertReference(cur, NewLink)
+
+            case "f":
+                m.FollowLink(cur)
+
+            case "b":
+                m.JumpBack()
+
+            case "alt+l":
+                m.OpenBrokenLinkList()
+
+            case "R":
+                m.OpenBacklinkList(cur)
+
+            case "E":
+                m.OpenPrompt("external source (text file, outline or directory): ", "", func(m *model, source string) {
+                    if "" != source {
+                        m.PushUndo()
+                        m.AddSubAfterThis(cur, NewExternal(source))
+                    }
+                })
+
+            case "!":
+                m.OpenPrompt("command: ", "", func(m *model, command string) {
+                    if "" != command {
+                        m.PushUndo()
+                        m.AddSubAfterThis(cur, NewCommand(command))
+                    }
+                })
+
+            case "r":
+                m.RefreshExternal(cur)
+
+            case "/":
+                m.StartSearch()
+
+            case "n":
+                m.nextMatch(1)
+
+            case "N":
+                m.nextMatch(-1)
+
+            case "esc":
+                m.search = nil
+
+            case "F":
+                m.OpenFilterPrompt()
+
+            case "C":
+                m.CycleCompletedMode()
+
+            case "z":
+                m.Hoist(cur)
+
+            case "Z":
+                m.StepUp()
+
+            case "alt+z":
+                m.Unhoist()
+
+            case "g":
+                m.StartJump()
+
+            case "#":
+                m.EditTags(cur)
+
+            case "T":
+                m.OpenTagList()
+
+            case "p":
+                m.OpenMetaEditor(cur)
+
+            case "V":
+                m.OpenTable(cur)
+
+            case "D":
+                m.SetDate(cur, metaDue)
+
+            case "S":
+                m.SetDate(cur, metaScheduled)
+
+            case "A":
+                m.OpenAgenda()
+
+            case "X":
+                m.ToggleTask(cur)
 
             case "v":
                 if nil != m.copiedItem {
@@ -820,6 +1378,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 m.Demote(cur)
 
             case "ctrl+p":
+                if !canHaveSubs(cur) {
+                    m.status = "this item cannot have subs"
+                    break
+                }
+
                 m.newestItem = m.AddNewItemAndEdit(cur)
                 // not pushing onto undo stack; happens either on confirm, or we don't care about the item
 
@@ -835,7 +1398,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                 m.Redo()
 
             case "ctrl+c", "q":
-                return m, tea.Quit
+                if cmd := m.RequestQuit(); nil != cmd {
+                    return m, cmd
+                }
 
             case "up", "k":
                 m.GoUp()
@@ -871,24 +1436,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
                     m.Collapse(cur)
                 } else {
                     // if already collapsed, collapse parent
-                    if nil != cur.GetParent() {
+                    if nil != cur.GetParent() && cur.GetParent() != m.hoisted {
                         m.Collapse(cur.GetParent())
                     }
                 }
 
             case "s":
-                m.SaveCurrentAs(m.filename)
+                m.Save()
 
-            /*
             case "O":
-                orgConfig := org.New()
-                orgDoc := orgConfig.Parse(strings.NewReader(""), "out.org")
-                fmt.Printf("O %s\n", orgDoc)
-            */
+                m.Export(ExportFilename(m.filename, ".opml"))
+
+            case "M":
+                m.Export(ExportFilename(m.filename, ".md"))
+
+            case "alt+m":
+                m.ExportMarkdown(ExportFilename(m.filename, ".md"), MarkdownExportOptions{Root: cur, OnlyExpanded: true})
+
+            case "H":
+                m.ExportHTML(ExportFilename(m.filename, ".html"))
+
+            case "B":
+                m.OpenBackupList()
+
+            case "W":
+                if 0 != m.newerVersion {
+                    m.status = m.newerVersionStatus()
+                } else if m.readOnly {
+                    m.readOnly = false
+                    m.status = fmt.Sprintf("saving to %s is allowed again", m.filename)
+                }
            }
         }
     }
 
+    if m.changeCount != changeCountBefore {
+        cmds = append(cmds, m.afterModification())
+    }
+
+    cmds = append(cmds, m.startCommands()...)
+
     // make sure cursor is in valid range
     if m.Cursor < 0 {
         m.Cursor = 0
@@ -928,12 +1515,15 @@ func drawItem(m *model, i int, item OItem, open_elements map[int]bool) string {
         cursor_right = " <"
     }
 
-    checked := " "
+    // only tasks have a check box
+    checked := "   "
     if item.IsChecked() {
-        checked = "✓"
+        checked = "[x]"
+    } else if IsTask(item) {
+        checked = "[ ]"
     }
 
-    level := item.Level(nil)
+    level := m.viewLevel(item)
 
     branches := ""
     level_indicator := ""
@@ -979,6 +1569,8 @@ func drawItem(m *model, i int, item OItem, open_elements map[int]bool) string {
         } else {
             level_indicator += " ▶ " // " ⊕ "
         }
+    } else if item.GetType() == OTypeProxyLink {
+        level_indicator += " ↪ "
     } else {
         level_indicator += " · " // " ▷ "
     }
@@ -1002,6 +1594,10 @@ func drawItem(m *model, i int, item OItem, open_elements map[int]bool) string {
 
     if item.IsChecked() {
         selected_style = selected_style.Strikethrough(true)
+
+        if completedDim == m.CompletedMode() {
+            selected_style = selected_style.Faint(true)
+        }
     }
 
     cur := m.linearized[m.Cursor]
@@ -1011,14 +1607,77 @@ func drawItem(m *model, i int, item OItem, open_elements map[int]bool) string {
         selected_style = selected_style.Bold(true)
     }
 
+    txt := item.GetTxt()
+
+    if item.GetType() == OTypeProxyLink {
+        if target := m.LinkTarget(item); nil != target {
+            txt = target.GetTxt()
+            selected_style = selected_style.Underline(true)
+        } else {
+            txt = fmt.Sprintf("⚠ broken link to %s", item.(*oitemlink).Target)
+
+            if m.Cursor != i {
+                selected_style = selected_style.Foreground(lipgloss.Color("196"))
+            }
+        }
+    }
+
+    rendered_txt := selected_style.Render(txt)
+
+    if nil != m.search {
+        rendered_txt = highlightMatches(txt, m.search.query, selected_style)
+    }
+
+    backlinks := ""
+
+    if count := len(m.Backlinks(item)); count > 0 {
+        backlinks = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(fmt.Sprintf(" ⇠%d", count))
+    }
+
+    prefix := fmt.Sprintf("%s %s", cursor_left, checked)
+
+    if nil != m.jump {
+        if label, ok := m.jumpLabelView(item); ok {
+            prefix = label
+        }
+    }
+
     if item.IsEdited() {
-        return fmt.Sprintf("%s %s%s%s%s%s\n", cursor_left, checked, level_indicator, m.textinput.View(), open_elements_indicator, cursor_right)
+        return fmt.Sprintf("%s%s%s%s%s\n", prefix, level_indicator, m.textinput.View(), open_elements_indicator, cursor_right)
     } else {
-        return fmt.Sprintf("%s %s%s%s%s%s\n", cursor_left, checked, level_indicator, selected_style.Render(item.GetTxt()), open_elements_indicator, cursor_right)
+        return fmt.Sprintf("%s%s%s%s%s\n", prefix, level_indicator, rendered_txt, m.progressView(item) + tagsView(item) + datesView(item, time.Now()) + backlinks + open_elements_indicator, cursor_right)
     }
 }
 
 func (m model) contentView() string {
+    if "" != m.pendingJournal {
+        return m.journalPromptView()
+    }
+
+    if m.choosingBackup {
+        return m.backupListView()
+    }
+
+    if nil != m.itemList {
+        return m.itemListView()
+    }
+
+    if nil != m.tagList {
+        return m.tagListView()
+    }
+
+    if nil != m.metaEditor {
+        return m.metaEditorView()
+    }
+
+    if nil != m.table {
+        return m.tableViewString()
+    }
+
+    if nil != m.agenda {
+        return m.agendaView()
+    }
+
     // keep track of which elements are open on each level (displayed part of subs, but more subs
     // will be painted after painting intermediate subs of higher levels)
     open_elements := make(map[int]bool)
@@ -1028,7 +1687,21 @@ func (m model) contentView() string {
     header_style := lipgloss.NewStyle().Background(color_yellow).Foreground(lipgloss.Color("0"));
     footer_style := lipgloss.NewStyle().Background(color_yellow).Foreground(lipgloss.Color("0"));
 
-    header_text := fmt.Sprintf("%s [%s]", m.Title.GetTxt(), m.filename)
+    modified := ""
+
+    if m.IsDirty() {
+        modified = " [+]"
+    }
+
+    if m.readOnly {
+        modified += " [read-only]"
+    }
+
+    if nil != m.filter {
+        modified += fmt.Sprintf(" [filter: %s]", m.filterExpr)
+    }
+
+    header_text := fmt.Sprintf("%s [%s]%s", m.breadcrumb(), m.filename, modified)
     s := header_style.Render(header_text) + "\n\n"
 
     //s += level_headers
@@ -1037,9 +1710,9 @@ func (m model) contentView() string {
         s += drawItem(&m, i, item, open_elements)
 
         if !item.IsLastSibling() {
-            open_elements[item.Level(nil)] = true
+            open_elements[m.viewLevel(item)] = true
         } else {
-            delete(open_elements, item.Level(nil))
+            delete(open_elements, m.viewLevel(item))
         }
     }
 
@@ -1051,11 +1724,24 @@ func (m model) contentView() string {
         copiedItemTxt = m.copiedItem.GetTxt()
     }
 
+    hidden := ""
+
+    if count := m.HiddenCompletedCount(); count > 0 {
+        hidden = fmt.Sprintf(" hidden: %d checked", count)
+    }
+
     if m.Cursor < len(m.linearized) {
         s += fmt.Sprintf(
-            "\n" + footer_style.Render("Press q to quit.      cursor: %d copied: %s") + "\n",
+            "\n" + footer_style.Render("Press q to quit.      cursor: %d copied: %s%s") + "\n",
             m.Cursor,
-            copiedItemTxt)
+            copiedItemTxt,
+            hidden)
+    }
+
+    if nil != m.prompt {
+        s += m.promptView()
+    } else if "" != m.status {
+        s += m.status + "\n"
     }
 
     visualizeUndoList := false
@@ -1092,5 +1778,10 @@ func (m model) View() string {
         return "\n  Initializing..."
     }
 
+    // shown instead of the viewport, which might be scrolled away from header and footer
+    if m.confirmingQuit {
+        return m.quitPromptView()
+    }
+
     return fmt.Sprintf("%s", m.viewport.View())
 }
@@ -0,0 +1,40 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestLinearizationSimple(t *testing.T) {
+    m := InitialModel()
+
+    if m.linearCount != 1 {
+        t.Error("Expected", 1, "for m.linearCount, but got", m.linearCount)
+    }
+
+    if len(m.Title.GetSubs()) != 1 {
+        t.Error("Expected", 1, "for m.Title.GetSubs(), but got", len(m.Title.GetSubs()))
+    }
+
+    if len(m.linearized) != 1 {
+        t.Error("Expected", 1, "for m.linearized, but got", len(m.linearized))
+    }
+
+    m.Expand(m.Title.GetSubs()[0])
+
+    if m.linearCount != 1 {
+        t.Error("Expected", 1, "for m.linearCount, but got", m.linearCount)
+    }
+
+    if len(m.linearized) != 1 {
+        t.Error("Expected", 1, "for m.linearized, but got", len(m.linearized))
+    }
+}
+
+func TestSetTitle(t *testing.T) {
+    m := InitialModel()
+
+    m.SetTitle("foo")
+    if m.Title.GetTxt() != "foo" {
+        t.Error("Expected", "foo", "for m.Title, but got", m.Title)
+    }
+}
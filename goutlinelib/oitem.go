@@ -1,8 +1,6 @@
 package goutlinelib
 
 import(
-    "errors"
-    "fmt"
     "time"
 )
 
@@ -26,19 +24,25 @@ type OItem interface {
     Init()
     GetType() OType
     GetId() string
+    SetId(id string)
     GetCreated() int64
     SetTimestampCreatedNow()
     GetChanged() int64
     SetTimestampChangedNow()
     GetTxt() string
     SetTxt(txt string)
+    GetBody() string
+    SetBody(body string)
     IsChecked() bool
     SetChecked(checked bool)
+    IsNumbered() bool
+    SetNumbered(numbered bool)
 
     GetSubs() []OItem
     SetSubs(subs []OItem)
 
     GetMeta() OItem
+    SetMeta(meta OItem)
 
     GetParent() OItem
     SetParent(item OItem)
@@ -80,6 +84,9 @@ type oitem struct {
     // main text
     Txt string
 
+    // free text below the main text (e.g. body and drawers of an org-mode headline)
+    Body string
+
     // use auto-numbering for subs
     Numbered bool
 
@@ -103,91 +110,70 @@ type oitem struct {
     edited bool
 }
 
-func IsOitemTypeEntry(temp map[string]interface{}) bool {
-    typeSwitch := temp["Type"]
-    
-    if  typeSwitch == nil || typeSwitch.(string) == "oitem" || typeSwitch.(string) == "" {
-        return true
+// storesSubs tells whether the subs of item are part of the outline, as
+// opposed to those of transclusions (which belong to the target) and external
+// items (which are generated).
+func storesSubs(item OItem) bool {
+    switch item.(type) {
+
+    case *oitemproxy, *oitemexternal:
+        return false
     }
 
-    return false
+    return true
 }
 
-func UnmarshalJSONOItem(temp map[string]interface{}) (item OItem, err error) {
-    err = nil
-
-    if nil == temp {
-        item = nil
-        return
-    }
+// canHaveSubs tells whether subs can be added to item.
+func canHaveSubs(item OItem) bool {
+    switch item.(type) {
 
-    if  IsOitemTypeEntry(temp) {
-        tmpItem := &oitem{}
-        err = tmpItem.UnmarshalOitemStruct(temp)
-        if nil == err {
-            item = tmpItem
-        } else {
-            err = fmt.Errorf("Error when unmarshalling item: %w", err)
-            return
-        }
-    } else {
-        err = errors.New("Unsupported type")
-        return
+    case *oitemlink, *oitemexternal:
+        return false
     }
 
-    return
+    return true
 }
 
-func (o *oitem) UnmarshalOitemStruct(temp map[string]interface{}) (err error) {
-    err = nil
+func IsOitemTypeEntry(temp map[string]interface{}) bool {
+    typeSwitch, _ := temp["Type"].(string)
+
+    return "oitem" == typeSwitch || "" == typeSwitch
+}
 
-    if IsOitemTypeEntry(temp) {
-        o.Type = "oitem"
-    } else {
-        err = errors.New("Unsupported type")
+// UnmarshalJSONOItem converts the generic JSON representation of an item; any
+// problem found on the way is reported as a LoadError.
+func UnmarshalJSONOItem(temp map[string]interface{}) (item OItem, err error) {
+    if nil == temp {
         return
     }
 
-    o.Id = temp["Id"].(string)
-    o.Created = int64(temp["Created"].(float64))
-    o.Changed = int64(temp["Changed"].(float64))
-    o.Txt = temp["Txt"].(string)
-    o.Numbered = temp["Numbered"].(bool)
-    o.Checked = temp["Checked"].(bool)
-    o.Expanded = temp["Expanded"].(bool)
-
-    tempMeta := temp["Meta"]
-    if nil != tempMeta {
-        var metaTmp OItem
-        metaTmp, err = UnmarshalJSONOItem(tempMeta.(map[string]interface{}))
-        
-        if err != nil {
-            err = fmt.Errorf("Could not UnmarshalJSONOItem: %w", err)
-            return
-        }
+    l := &jsonLoader{}
+    item = l.item(temp, "")
 
-        o.Meta = metaTmp
+    if len(l.problems) > 0 {
+        err = &LoadError{Problems: l.problems}
     }
 
-    tempSubs := temp["Subs"]
-    if nil != tempSubs {
-        arr := tempSubs.([]interface{})
-        o.Subs = make([]OItem, 0, len(arr))
-        for _, cur := range arr {
-            tempCur := cur.(map[string]interface{})
-            var new_item OItem
-            new_item, err = UnmarshalJSONOItem(tempCur)
-
-            if err != nil {
-                err = fmt.Errorf("Could not create sub: %w", err)
-                return
-            }
+    return
+}
 
-            o.Subs = append(o.Subs, new_item)
-        }
-    }    
+func (o *oitem) unmarshalOitemStruct(l *jsonLoader, temp map[string]interface{}, path string) {
+    o.Type = "oitem"
 
-    return
+    o.Id = l.getString(temp, path, "Id")
+    o.Created = l.getInt64(temp, path, "Created")
+    o.Changed = l.getInt64(temp, path, "Changed")
+    o.Txt = l.getString(temp, path, "Txt")
+    o.Body = l.getString(temp, path, "Body")
+    o.Numbered = l.getBool(temp, path, "Numbered")
+    o.Checked = l.getBool(temp, path, "Checked")
+    o.Expanded = l.getBool(temp, path, "Expanded")
+
+    if tempMeta := temp["Meta"]; nil != tempMeta {
+        o.Meta = l.item(tempMeta, jsonPath(path, "Meta"))
+    }
+
+    o.Subs = l.subs(temp, path)
 }
 
 func (o *oitem) Init() {
@@ -208,6 +194,10 @@ func (o *oitem) GetId() string {
     return o.Id
 }
 
+func (o *oitem) SetId(id string) {
+    o.Id = id
+}
+
 func (o *oitem) GetCreated() int64 {
     return o.Created
 }
@@ -224,6 +214,14 @@ func (o *oitem) SetTxt(txt string) {
     o.Txt = txt
 }
 
+func (o *oitem) GetBody() string {
+    return o.Body
+}
+
+func (o *oitem) SetBody(body string) {
+    o.Body = body
+}
+
 func (o *oitem) IsChecked() bool {
     return o.Checked
 }
@@ -232,6 +230,14 @@ func (o *oitem) SetChecked(checked bool) {
     o.Checked = checked
 }
 
+func (o *oitem) IsNumbered() bool {
+    return o.Numbered
+}
+
+func (o *oitem) SetNumbered(numbered bool) {
+    o.Numbered = numbered
+}
+
 func (o *oitem) GetSubs() []OItem {
     return o.Subs
 }
@@ -244,6 +250,10 @@ func (o *oitem) GetMeta() OItem {
     return o.Meta
 }
 
+func (o *oitem) SetMeta(meta OItem) {
+    o.Meta = meta
+}
+
 func (o *oitem) GetParent() OItem {
     return o.parent
 }
@@ -279,7 +289,8 @@ func (o *oitem) SetTimestampChangedNow() {
 }
 
 func (o *oitem) DeepCopy() OItem {
-    result := &oitem{Txt: o.Txt}
+    // a copy is a new item, so it needs an id of its own
+    result := &oitem{Type: "oitem", Id: NewId(), Txt: o.Txt, Body: o.Body}
     result.SetTimestampCreatedNow()
 
     result.Numbered = o.Numbered
@@ -299,7 +310,7 @@ func (o *oitem) DeepCopy() OItem {
 }
 
 func (o *oitem) DeepCopyForUndo() OItem {
-    result := &oitem{Txt: o.Txt}
+    result := &oitem{Txt: o.Txt, Body: o.Body}
 
     result.Numbered = o.Numbered
     result.Checked = o.Checked
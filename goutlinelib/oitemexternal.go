@@ -0,0 +1,527 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// kinds of external sources
+const (
+    // text file, one item per line, indentation gives the nesting
+    externalKindText = "text"
+
+    // output of a shell command, one item per line; run asynchronously
+    externalKindCommand = "command"
+
+    // outline in any format goutline can load, mounted read-only
+    externalKindOutline = "outline"
+
+    // directory listing; subdirectories are external items themselves
+    externalKindDir = "dir"
+)
+
+// oitemexternal is an item whose subs are generated from an external source
+// when it is expanded; only the reference to the source is saved.
+type oitemexternal struct {
+    Type string
+
+    // unique id
+    Id string
+
+    // time created
+    Created int64
+
+    // time last changed
+    Changed int64
+
+    // label; the source is shown if empty
+    Txt string
+
+    // path of the source, relative paths are relative to the outline's file;
+    // the command line for commands
+    Source string
+
+    // one of the externalKind constants; guessed from the source if empty
+    Kind string
+
+    // expansion state; always false for commands, see expandedCommand
+    Expanded bool
+
+    // settings, e.g. for commands
+    Meta OItem
+
+    // directory that relative sources are resolved against
+    base string
+
+    loaded bool
+
+    // commands are only expanded for the current session, so that opening a
+    // file never runs them
+    expandedCommand bool
+
+    // a command is running; incremented for every run, so that the output of
+    // outdated runs can be recognized
+    running bool
+    runSeq int
+
+    subs []OItem
+
+    parent OItem
+
+    edited bool
+
+    otype OType
+}
+
+func NewExternal(source string) OItem {
+    result := &oitemexternal{Id: NewId(), Source: source}
+    result.Init()
+    result.SetTimestampCreatedNow()
+
+    return result
+}
+
+func (o *oitemexternal) unmarshalExternalStruct(l *jsonLoader, temp map[string]interface{}, path string) {
+    o.Init()
+
+    o.Id = l.getString(temp, path, "Id")
+    o.Created = l.getInt64(temp, path, "Created")
+    o.Changed = l.getInt64(temp, path, "Changed")
+    o.Txt = l.getString(temp, path, "Txt")
+    o.Source = l.getString(temp, path, "Source")
+    o.Kind = l.getString(temp, path, "Kind")
+    o.Expanded = l.getBool(temp, path, "Expanded") && externalKindCommand != o.Kind
+
+    if tempMeta := temp["Meta"]; nil != tempMeta {
+        o.Meta = l.item(tempMeta, jsonPath(path, "Meta"))
+    }
+
+    if "" == o.Source {
+        l.problem(jsonPath(path, "Source"), "missing source of external item")
+    }
+}
+
+// setBase changes the directory relative sources are resolved against; the
+// subs are loaded again, as they might come from a different source now.
+func (o *oitemexternal) setBase(base string) {
+    if base != o.base {
+        o.base = base
+        o.Refresh()
+    }
+}
+
+func (o *oitemexternal) sourcePath() string {
+    if filepath.IsAbs(o.Source) {
+        return o.Source
+    }
+
+    return filepath.Join(o.base, o.Source)
+}
+
+func (o *oitemexternal) kind() string {
+    if "" != o.Kind {
+        return o.Kind
+    }
+
+    if info, err := os.Stat(o.sourcePath()); nil == err && info.IsDir() {
+        return externalKindDir
+    }
+
+    switch strings.ToLower(filepath.Ext(o.Source)) {
+
+    case ".json", ".org", ".opml", ".md", ".markdown":
+        return externalKindOutline
+    }
+
+    return externalKindText
+}
+
+// Refresh drops the generated subs, so that they are loaded again when needed.
+func (o *oitemexternal) Refresh() {
+    o.loaded = false
+    o.running = false
+    o.subs = nil
+}
+
+func (o *oitemexternal) load() {
+    o.loaded = true
+
+    var subs []OItem
+    var err error
+
+    switch o.kind() {
+
+    case externalKindDir:
+        subs, err = loadExternalDir(o.sourcePath())
+
+    case externalKindOutline:
+        if o.isMountedAbove() {
+            // e.g. an outline that mounts itself would be loaded endlessly
+            err = fmt.Errorf("cycle: %s is already mounted above", o.Source)
+        } else {
+            subs, err = loadExternalOutline(o.sourcePath())
+        }
+
+    default:
+        subs, err = loadExternalText(o.sourcePath())
+    }
+
+    if nil != err {
+        subs = append(subs, &oitem{Type: "oitem", Txt: fmt.Sprintf("⚠ %v", err)})
+    }
+
+    for _, sub := range subs {
+        sub.SetParent(o)
+    }
+
+    o.subs = subs
+}
+
+func absPath(filename string) string {
+    if abs, err := filepath.Abs(filename); nil == err {
+        return abs
+    }
+
+    return filepath.Clean(filename)
+}
+
+// isMountedAbove tells whether the outline o mounts is being shown by one of
+// its ancestors already, i.e. whether o is part of a cycle of mounts.
+func (o *oitemexternal) isMountedAbove() bool {
+    path := absPath(o.sourcePath())
+
+    for cur := o.parent; nil != cur; cur = cur.GetParent() {
+        if above, ok := cur.(*oitemexternal); ok && externalKindOutline == above.kind() && path == absPath(above.sourcePath()) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func loadExternalText(filename string) ([]OItem, error) {
+    b, err := ioutil.ReadFile(filename)
+
+    if nil != err {
+        return nil, err
+    }
+
+    root := &oitem{Type: "oitem"}
+    open := []markdownListEntry{{indent: -1, item: root}}
+
+    for _, line := range strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n") {
+        if "" == strings.TrimSpace(line) {
+            continue
+        }
+
+        indent := 0
+
+        for _, c := range line {
+            if ' ' == c {
+                indent++
+            } else if '\t' == c {
+                indent += 4
+            } else {
+                break
+            }
+        }
+
+        for open[len(open) - 1].indent >= indent {
+            open = open[:len(open) - 1]
+        }
+
+        item := &oitem{Type: "oitem", Txt: strings.TrimSpace(line)}
+        parent := open[len(open) - 1].item
+        parent.AddSubAt(item, len(parent.GetSubs()))
+        open = append(open, markdownListEntry{indent: indent, item: item})
+    }
+
+    return root.Subs, nil
+}
+
+// absolute paths of the outlines being loaded by loadExternalOutline; loading
+// an outline linearizes it, which loads the outlines it mounts in turn
+var loadingOutlines = make(map[string]bool)
+
+func loadExternalOutline(filename string) ([]OItem, error) {
+    path := absPath(filename)
+
+    if loadingOutlines[path] {
+        return nil, fmt.Errorf("cycle: %s is already mounted above", filename)
+    }
+
+    loadingOutlines[path] = true
+    defer delete(loadingOutlines, path)
+
+    m, err := modelFromFileAs(filename, FormatForFilename(filename))
+
+    if nil != err && nil == m.Title {
+        return nil, err
+    }
+
+    // a repaired outline is still worth showing, together with the problems
+    return m.Title.GetSubs(), err
+}
+
+func loadExternalDir(dirname string) ([]OItem, error) {
+    entries, err := ioutil.ReadDir(dirname)
+
+    if nil != err {
+        return nil, err
+    }
+
+    result := make([]OItem, 0, len(entries))
+
+    for _, entry := range entries {
+        if entry.IsDir() {
+            sub := &oitemexternal{Txt: entry.Name() + "/", Source: filepath.Join(dirname, entry.Name()), Kind: externalKindDir}
+            sub.Init()
+            result = append(result, sub)
+        } else {
+            result = append(result, &oitem{Type: "oitem", Txt: entry.Name()})
+        }
+    }
+
+    return result, nil
+}
+
+func (o *oitemexternal) Init() {
+    o.Type = "oitemexternal"
+    o.otype = OTypeProxyExternal
+
+    if nil != o.Meta {
+        o.Meta.Init()
+    }
+}
+
+func (o *oitemexternal) GetType() OType {
+    return o.otype
+}
+
+func (o *oitemexternal) GetId() string {
+    return o.Id
+}
+
+func (o *oitemexternal) SetId(id string) {
+    o.Id = id
+}
+
+func (o *oitemexternal) GetCreated() int64 {
+    return o.Created
+}
+
+func (o *oitemexternal) GetChanged() int64 {
+    return o.Changed
+}
+
+func (o *oitemexternal) GetTxt() string {
+    if "" == o.Txt {
+        return o.Source
+    }
+
+    return o.Txt
+}
+
+func (o *oitemexternal) SetTxt(txt string) {
+    o.Txt = txt
+}
+
+func (o *oitemexternal) GetBody() string {
+    return ""
+}
+
+func (o *oitemexternal) SetBody(body string) {
+}
+
+func (o *oitemexternal) IsChecked() bool {
+    return false
+}
+
+func (o *oitemexternal) SetChecked(checked bool) {
+}
+
+func (o *oitemexternal) IsNumbered() bool {
+    return false
+}
+
+func (o *oitemexternal) SetNumbered(numbered bool) {
+}
+
+// GetSubs loads the subs from the source the first time they are needed
+// while the item is expanded.
+func (o *oitemexternal) GetSubs() []OItem {
+    if !o.loaded && o.IsExpanded() {
+        if externalKindCommand == o.kind() {
+            // the output is filled in asynchronously, see startCommands
+            if nil == o.subs {
+                o.subs = []OItem{&oitem{Type: "oitem", Txt: "running …", parent: o}}
+            }
+        } else {
+            o.load()
+        }
+    }
+
+    return o.subs
+}
+
+func (o *oitemexternal) SetSubs(subs []OItem) {
+}
+
+func (o *oitemexternal) GetMeta() OItem {
+    return o.Meta
+}
+
+func (o *oitemexternal) SetMeta(meta OItem) {
+    o.Meta = meta
+}
+
+func (o *oitemexternal) GetParent() OItem {
+    return o.parent
+}
+
+func (o *oitemexternal) SetParent(item OItem) {
+    o.parent = item
+}
+
+func (o *oitemexternal) IsExpanded() bool {
+    if externalKindCommand == o.Kind {
+        return o.expandedCommand
+    }
+
+    return o.Expanded
+}
+
+func (o *oitemexternal) SetExpanded(expanded bool) {
+    if externalKindCommand == o.Kind {
+        o.expandedCommand = expanded
+    } else {
+        o.Expanded = expanded
+    }
+}
+
+func (o *oitemexternal) IsEdited() bool {
+    return o.edited
+}
+
+func (o *oitemexternal) SetEdited(edited bool) {
+    o.edited = edited
+}
+
+func (o *oitemexternal) SetTimestampCreatedNow() {
+    o.Created = time.Now().UTC().Unix()
+}
+
+func (o *oitemexternal) SetTimestampChangedNow() {
+    o.Changed = time.Now().UTC().Unix()
+}
+
+func (o *oitemexternal) DeepCopy() OItem {
+    result := &oitemexternal{Id: NewId(), Txt: o.Txt, Source: o.Source, Kind: o.Kind, base: o.base}
+    result.Init()
+    result.SetTimestampCreatedNow()
+
+    if nil != o.Meta {
+        result.Meta = o.Meta.DeepCopy()
+    }
+
+    return result
+}
+
+func (o *oitemexternal) DeepCopyForUndo() OItem {
+    // the subs are loaded again when needed
+    result := &oitemexternal{Id: o.Id, Created: o.Created, Changed: o.Changed, Txt: o.Txt, Source: o.Source, Kind: o.Kind, Expanded: o.Expanded, expandedCommand: o.expandedCommand, base: o.base}
+    result.Init()
+
+    if nil != o.Meta {
+        result.Meta = o.Meta.DeepCopyForUndo()
+    }
+
+    return result
+}
+
+func (o *oitemexternal) AddSubAfterThis(item OItem) {
+    if nil == o.parent || nil == item {
+        return
+    }
+
+    pos := o.IndexOfItem()
+
+    if -1 == pos {
+        return
+    }
+
+    o.parent.AddSubAt(item, pos + 1)
+}
+
+func (o *oitemexternal) AddSubAt(item OItem, pos int) {
+}
+
+func (o *oitemexternal) Delete(item OItem) {
+}
+
+func (o *oitemexternal) IsFirstSibling() bool {
+    if (nil != o.parent) && (o.parent.GetSubs()[0] == o) {
+        return true
+    }
+
+    return false
+}
+
+func (o *oitemexternal) IsLastSibling() bool {
+    if (nil != o.parent) && (o.parent.GetSubs()[len(o.parent.GetSubs()) - 1] == o) {
+        return true
+    }
+
+    return false
+}
+
+// HasSubs assumes that there are subs until the source has been loaded, so
+// that the item can be expanded.
+func (o *oitemexternal) HasSubs() bool {
+    return !o.loaded || 0 != len(o.subs)
+}
+
+func (o *oitemexternal) Level(upTo OItem) int {
+    level := 0
+    var cur OItem = o.parent
+
+    if nil != upTo {
+        level++
+    }
+
+    for cur != upTo {
+        cur = cur.GetParent()
+        level++
+    }
+
+    return level
+}
+
+func (o *oitemexternal) HasSub(sub OItem) bool {
+    for _, item := range o.subs {
+        if item == sub || item.HasSub(sub) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func (o *oitemexternal) IndexOfItem() int {
+    result := -1
+
+    if nil != o.parent {
+        for i, cur := range o.parent.GetSubs() {
+            if cur == o {
+                result = i
+                break
+            }
+        }
+    }
+
+    return result
+}
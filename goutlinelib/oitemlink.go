@@ -0,0 +1,265 @@
+package goutlinelib
+
+import(
+    "time"
+)
+
+// oitemlink refers to another item by its id; unlike a transclusion, it does
+// not show the target's subs, but allows jumping to the target.
+type oitemlink struct {
+    Type string
+
+    // unique id
+    Id string
+
+    // time created
+    Created int64
+
+    // time last changed
+    Changed int64
+
+    // id of the linked item; target is resolved from it after loading
+    Target string
+
+    target OItem
+
+    parent OItem
+
+    edited bool
+
+    otype OType
+}
+
+func NewLink(target OItem) OItem {
+    // link to the original instead of a transclusion
+    if proxy, ok := target.(*oitemproxy); ok {
+        target = proxy.target
+    }
+
+    if "" == target.GetId() {
+        target.SetId(NewId())
+    }
+
+    result := &oitemlink{Id: NewId(), target: target, Target: target.GetId()}
+    result.Init()
+    result.SetTimestampCreatedNow()
+
+    return result
+}
+
+func (o *oitemlink) unmarshalLinkStruct(l *jsonLoader, temp map[string]interface{}, path string) {
+    o.Init()
+
+    o.Id = l.getString(temp, path, "Id")
+    o.Created = l.getInt64(temp, path, "Created")
+    o.Changed = l.getInt64(temp, path, "Changed")
+    o.Target = l.getString(temp, path, "Target")
+
+    if "" == o.Target {
+        l.problem(jsonPath(path, "Target"), "missing id of linked item")
+    }
+}
+
+// GetTarget returns the id of the linked item.
+func (o *oitemlink) GetTarget() string {
+    return o.Target
+}
+
+func (o *oitemlink) Init() {
+    o.Type = "oitemlink"
+    o.otype = OTypeProxyLink
+}
+
+func (o *oitemlink) GetType() OType {
+    return o.otype
+}
+
+func (o *oitemlink) GetId() string {
+    return o.Id
+}
+
+func (o *oitemlink) SetId(id string) {
+    o.Id = id
+}
+
+func (o *oitemlink) GetCreated() int64 {
+    return o.Created
+}
+
+func (o *oitemlink) GetChanged() int64 {
+    return o.Changed
+}
+
+// GetTxt returns the current text of the target, or nothing if the link is
+// broken.
+func (o *oitemlink) GetTxt() string {
+    if nil == o.target {
+        return ""
+    }
+
+    return o.target.GetTxt()
+}
+
+func (o *oitemlink) SetTxt(txt string) {
+    if nil != o.target {
+        o.target.SetTxt(txt)
+    }
+}
+
+func (o *oitemlink) GetBody() string {
+    return ""
+}
+
+func (o *oitemlink) SetBody(body string) {
+}
+
+func (o *oitemlink) IsChecked() bool {
+    return false
+}
+
+func (o *oitemlink) SetChecked(checked bool) {
+}
+
+func (o *oitemlink) IsNumbered() bool {
+    return false
+}
+
+func (o *oitemlink) SetNumbered(numbered bool) {
+}
+
+func (o *oitemlink) GetSubs() []OItem {
+    return nil
+}
+
+func (o *oitemlink) SetSubs(subs []OItem) {
+}
+
+func (o *oitemlink) GetMeta() OItem {
+    return nil
+}
+
+func (o *oitemlink) SetMeta(meta OItem) {
+}
+
+func (o *oitemlink) GetParent() OItem {
+    return o.parent
+}
+
+func (o *oitemlink) SetParent(item OItem) {
+    o.parent = item
+}
+
+func (o *oitemlink) IsExpanded() bool {
+    return false
+}
+
+func (o *oitemlink) SetExpanded(expanded bool) {
+}
+
+func (o *oitemlink) IsEdited() bool {
+    return o.edited
+}
+
+func (o *oitemlink) SetEdited(edited bool) {
+    o.edited = edited
+}
+
+func (o *oitemlink) SetTimestampCreatedNow() {
+    o.Created = time.Now().UTC().Unix()
+}
+
+func (o *oitemlink) SetTimestampChangedNow() {
+    o.Changed = time.Now().UTC().Unix()
+}
+
+func (o *oitemlink) DeepCopy() OItem {
+    // a copy still links to the same item
+    result := &oitemlink{Id: NewId(), target: o.target, Target: o.Target}
+    result.Init()
+    result.SetTimestampCreatedNow()
+
+    return result
+}
+
+func (o *oitemlink) DeepCopyForUndo() OItem {
+    // target is resolved within the restored tree, see resolveProxies
+    result := &oitemlink{Id: o.Id, Created: o.Created, Changed: o.Changed, Target: o.Target}
+    result.Init()
+
+    return result
+}
+
+func (o *oitemlink) AddSubAfterThis(item OItem) {
+    if nil == o.parent || nil == item {
+        return
+    }
+
+    pos := o.IndexOfItem()
+
+    if -1 == pos {
+        return
+    }
+
+    o.parent.AddSubAt(item, pos + 1)
+}
+
+func (o *oitemlink) AddSubAt(item OItem, pos int) {
+}
+
+func (o *oitemlink) Delete(item OItem) {
+}
+
+func (o *oitemlink) IsFirstSibling() bool {
+    if (nil != o.parent) && (o.parent.GetSubs()[0] == o) {
+        return true
+    }
+
+    return false
+}
+
+func (o *oitemlink) IsLastSibling() bool {
+    if (nil != o.parent) && (o.parent.GetSubs()[len(o.parent.GetSubs()) - 1] == o) {
+        return true
+    }
+
+    return false
+}
+
+func (o *oitemlink) HasSubs() bool {
+    return false
+}
+
+func (o *oitemlink) Level(upTo OItem) int {
+    level := 0
+    var cur OItem = o.parent
+
+    if nil != upTo {
+        level++
+    }
+
+    for cur != upTo {
+        cur = cur.GetParent()
+        level++
+    }
+
+    return level
+}
+
+func (o *oitemlink) HasSub(sub OItem) bool {
+    return false
+}
+
+func (o *oitemlink) IndexOfItem() int {
+    result := -1
+
+    if nil != o.parent {
+        for i, cur := range o.parent.GetSubs() {
+            if cur == o {
+                result = i
+                break
+            }
+        }
+    }
+
+    return result
+}
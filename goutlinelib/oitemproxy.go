@@ -1,5 +1,9 @@
 package goutlinelib
 
+import(
+    "fmt"
+)
+
 type oitemproxy struct {
     Type string
 
@@ -9,6 +13,9 @@ type oitemproxy struct {
     // expansion state
     Expanded bool
 
+    // id of the transcluded item; target is resolved from it after loading
+    Target string
+
     target OItem
 
     parent OItem
@@ -21,10 +28,92 @@ type oitemproxy struct {
 }
 
 func NewProxy(target OItem) OItem {
-    result := &oitemproxy{target: target}
+    // transclude the original instead of a transclusion
+    if proxy, ok := target.(*oitemproxy); ok {
+        target = proxy.target
+    }
+
+    if "" == target.GetId() {
+        target.SetId(NewId())
+    }
+
+    result := &oitemproxy{Id: NewId(), target: target, Target: target.GetId()}
+    result.Init()
+
     return result
 }
 
+// newDanglingTarget creates a placeholder for a transcluded item that could
+// not be found, so that the proxy stays visible and keeps its reference.
+func newDanglingTarget(id string) OItem {
+    return &oitem{Type: "oitem", Txt: fmt.Sprintf("⚠ missing transcluded item %s", id)}
+}
+
+func (o *oitemproxy) unmarshalProxyStruct(l *jsonLoader, temp map[string]interface{}, path string) {
+    o.Init()
+
+    o.Id = l.getString(temp, path, "Id")
+    o.Expanded = l.getBool(temp, path, "Expanded")
+    o.Target = l.getString(temp, path, "Target")
+
+    if "" == o.Target {
+        l.problem(jsonPath(path, "Target"), "missing id of transcluded item")
+    }
+}
+
+// resolveProxies points all transclusions and links below root to the items
+// with their target ids; transclusions of unknown items get a placeholder,
+// links to them are broken.
+func resolveProxies(root OItem) {
+    index := make(map[string]OItem)
+    var proxies []*oitemproxy
+    var links []*oitemlink
+
+    var walk func(item OItem)
+
+    walk = func(item OItem) {
+        if proxy, ok := item.(*oitemproxy); ok {
+            // subs of a transclusion are transclusions themselves
+            proxies = append(proxies, proxy)
+            return
+        }
+
+        if link, ok := item.(*oitemlink); ok {
+            links = append(links, link)
+            return
+        }
+
+        if "" != item.GetId() {
+            index[item.GetId()] = item
+        }
+
+        // generated items cannot be referred to
+        if !storesSubs(item) {
+            return
+        }
+
+        for _, sub := range item.GetSubs() {
+            walk(sub)
+        }
+    }
+
+    walk(root)
+
+    for _, proxy := range proxies {
+        if target, found := index[proxy.Target]; found {
+            proxy.target = target
+        } else {
+            proxy.target = newDanglingTarget(proxy.Target)
+        }
+
+        proxy.cachedProxiedSubs = nil
+    }
+
+    for _, link := range links {
+        link.target = index[link.Target]
+    }
+}
+
 func (o *oitemproxy) Init() {
     o.Type = "oitemproxy"
     o.otype = OTypeProxyTransclude
@@ -38,6 +127,10 @@ func (o *oitemproxy) GetId() string {
     return o.Id
 }
 
+func (o *oitemproxy) SetId(id string) {
+    o.Id = id
+}
+
 func (o *oitemproxy) GetCreated() int64 {
     return o.target.GetCreated()
 }
@@ -54,6 +147,14 @@ func (o *oitemproxy) SetTxt(txt string) {
     o.target.SetTxt(txt)
 }
 
+func (o *oitemproxy) GetBody() string {
+    return o.target.GetBody()
+}
+
+func (o *oitemproxy) SetBody(body string) {
+    o.target.SetBody(body)
+}
+
 func (o *oitemproxy) IsChecked() bool {
     return o.target.IsChecked()
 }
@@ -62,6 +163,14 @@ func (o *oitemproxy) SetChecked(checked bool) {
     o.target.SetChecked(checked)
 }
 
+func (o *oitemproxy) IsNumbered() bool {
+    return o.target.IsNumbered()
+}
+
+func (o *oitemproxy) SetNumbered(numbered bool) {
+    o.target.SetNumbered(numbered)
+}
+
 func (o *oitemproxy) GetSubs() []OItem {
     if len(o.cachedProxiedSubs) != len(o.target.GetSubs()) {
         o.cachedProxiedSubs = make([]OItem, 0, len(o.target.GetSubs()))
@@ -82,6 +191,10 @@ func (o *oitemproxy) GetMeta() OItem {
     return o.target.GetMeta()
 }
 
+func (o *oitemproxy) SetMeta(meta OItem) {
+    o.target.SetMeta(meta)
+}
+
 func (o *oitemproxy) GetParent() OItem {
     return o.parent
 }
@@ -117,7 +230,11 @@ func (o *oitemproxy) DeepCopy() OItem {
 }
 
 func (o *oitemproxy) DeepCopyForUndo() OItem {
-    return o.target.DeepCopyForUndo()
+    // target is resolved within the restored tree, see resolveProxies
+    result := &oitemproxy{Id: o.Id, Expanded: o.Expanded, Target: o.Target}
+    result.Init()
+
+    return result
 }
 
 func (o *oitemproxy) AddSubAfterThis(item OItem) {
@@ -0,0 +1,114 @@
+package goutlinelib
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+)
+
+func loadFromJSON(t *testing.T, b []byte) model {
+    var m model
+
+    if err := json.Unmarshal(b, &m); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if len(m.loadProblems) != 0 {
+        t.Fatal("Unexpected problems", m.loadProblems)
+    }
+
+    m.CommonPostInit()
+
+    return m
+}
+
+func TestProxySurvivesSaveAndLoad(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    target.SetTxt("target")
+    m.AddNewItem(target).SetTxt("sub of target")
+
+    m.AddSubAfterThis(target, NewProxy(target))
+
+    b, err := json.Marshal(m)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    loaded := loadFromJSON(t, b)
+    subs := loaded.Title.GetSubs()
+
+    if len(subs) != 2 || subs[1].GetType() != OTypeProxyTransclude {
+        t.Fatal("Expected transclusion after loading, but got", subs)
+    }
+
+    if subs[1].GetTxt() != "target" || len(subs[1].GetSubs()) != 1 {
+        t.Error("Expected transclusion to show the target, but got", subs[1].GetTxt())
+    }
+
+    subs[1].SetTxt("changed via transclusion")
+
+    if subs[0].GetTxt() != "changed via transclusion" {
+        t.Error("Expected target to be shared with the transclusion, but got", subs[0].GetTxt())
+    }
+}
+
+func TestDanglingProxy(t *testing.T) {
+    loaded := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "a"}, {"Type": "oitemproxy", "Target": "gone"}]}}`))
+    proxy := loaded.Title.GetSubs()[1]
+
+    if !strings.Contains(proxy.GetTxt(), "gone") {
+        t.Error("Expected placeholder mentioning the missing id, but got", proxy.GetTxt())
+    }
+
+    b, _ := json.Marshal(loaded)
+
+    if !strings.Contains(string(b), `"Target":"gone"`) {
+        t.Error("Expected dangling reference to be kept when saving, but got", string(b))
+    }
+}
+
+func TestProxySurvivesUndo(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+
+    m.PushUndo()
+    m.AddSubAfterThis(target, NewProxy(target))
+
+    m.PushUndo()
+    m.AddNewItem(m.Title)
+
+    m.PopUndo()
+
+    proxy := m.Title.GetSubs()[1]
+
+    if proxy.GetType() != OTypeProxyTransclude {
+        t.Fatal("Expected transclusion after undo, but got", proxy)
+    }
+
+    proxy.SetTxt("shared")
+
+    if m.Title.GetSubs()[0].GetTxt() != "shared" {
+        t.Error("Expected transclusion to point into the restored tree")
+    }
+}
+
+func TestTransclusionAfterUndo(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    target.SetTxt("A")
+    m.refItem = target
+
+    m.PushUndo()
+    m.AddNewItem(m.Title)
+    m.PopUndo()
+
+    m.InsertReference(m.Title.GetSubs()[0], NewProxy)
+
+    proxy := m.Title.GetSubs()[1]
+    proxy.SetTxt("edited via proxy")
+
+    if m.Title.GetSubs()[0].GetTxt() != "edited via proxy" {
+        t.Error("Expected transclusion of the restored item, but the item has", m.Title.GetSubs()[0].GetTxt())
+    }
+}
@@ -0,0 +1,301 @@
+package goutlinelib
+
+import(
+    "bytes"
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// namespace of the attributes that carry goutline specific fields
+const opmlNamespace = "https://github.com/pmf/goutline"
+const opmlPrefix = "goutline"
+
+func opmlAttr(name string, value string) xml.Attr {
+    return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+func isOpmlElement(elem xml.Name, name string) bool {
+    return strings.EqualFold(elem.Local, name)
+}
+
+func isGoutlineAttr(attr xml.Attr) bool {
+    // the decoder resolves declared prefixes to the namespace; an undeclared
+    // prefix is left as it is
+    return attr.Name.Space == opmlNamespace || attr.Name.Space == opmlPrefix
+}
+
+func applyOpmlAttrs(item *oitem, attrs []xml.Attr) (err error) {
+    for _, attr := range attrs {
+        if isGoutlineAttr(attr) {
+            switch attr.Name.Local {
+
+            case "id":
+                item.Id = attr.Value
+
+            case "created":
+                item.Created, err = strconv.ParseInt(attr.Value, 10, 64)
+
+            case "changed":
+                item.Changed, err = strconv.ParseInt(attr.Value, 10, 64)
+
+            case "checked":
+                item.Checked, err = strconv.ParseBool(attr.Value)
+
+            case "numbered":
+                item.Numbered, err = strconv.ParseBool(attr.Value)
+
+            case "expanded":
+                item.Expanded, err = strconv.ParseBool(attr.Value)
+
+            case "meta":
+                for _, line := range strings.Split(attr.Value, "\n") {
+                    if key, value, ok := parseMetaEntry(line); ok {
+                        SetMetaValue(item, key, value)
+                    }
+                }
+            }
+
+            if err != nil {
+                err = fmt.Errorf("Invalid value for attribute %s:%s: %w", opmlPrefix, attr.Name.Local, err)
+                return
+            }
+        } else if "" == attr.Name.Space {
+            switch attr.Name.Local {
+
+            case "text":
+                item.Txt = attr.Value
+
+            case "_note":
+                item.Body = attr.Value
+            }
+        }
+    }
+
+    return
+}
+
+// UnmarshalOPML builds the outline from an OPML document; nested outline
+// elements become subs, the head's title becomes the title.
+func (m *model) UnmarshalOPML(data []byte) (err error) {
+    title := &oitem{Type: "oitem"}
+
+    decoder := xml.NewDecoder(bytes.NewReader(data))
+
+    // outline elements that are currently open
+    open := []OItem{title}
+    inBody := false
+    inTitle := false
+
+    for {
+        var token xml.Token
+        token, err = decoder.Token()
+
+        if err == io.EOF {
+            err = nil
+            break
+        }
+
+        if err != nil {
+            err = fmt.Errorf("Could not parse OPML: %w", err)
+            return
+        }
+
+        switch t := token.(type) {
+
+        case xml.StartElement:
+            if isOpmlElement(t.Name, "body") {
+                inBody = true
+            } else if isOpmlElement(t.Name, "title") && !inBody {
+                inTitle = true
+            } else if isOpmlElement(t.Name, "outline") && inBody {
+                item := &oitem{Type: "oitem"}
+                err = applyOpmlAttrs(item, t.Attr)
+
+                if err != nil {
+                    return
+                }
+
+                parent := open[len(open) - 1]
+                parent.AddSubAt(item, len(parent.GetSubs()))
+                open = append(open, item)
+            }
+
+        case xml.EndElement:
+            if isOpmlElement(t.Name, "body") {
+                inBody = false
+            } else if isOpmlElement(t.Name, "title") {
+                inTitle = false
+            } else if isOpmlElement(t.Name, "outline") && len(open) > 1 {
+                open = open[:len(open) - 1]
+            }
+
+        case xml.CharData:
+            if inTitle {
+                title.Txt += string(t)
+            }
+        }
+    }
+
+    if len(open) != 1 {
+        err = errors.New("Unbalanced outline elements in OPML")
+        return
+    }
+
+    title.Txt = strings.TrimSpace(title.Txt)
+
+    if !title.HasSubs() {
+        title.AddSubAt(&oitem{Type: "oitem"}, 0)
+    }
+
+    m.Title = title
+    m.Config = &oitem{}
+    m.Cursor = 0
+
+    return
+}
+
+type opmlExportVisitor struct {
+    encoder *xml.Encoder
+
+    // number of outline elements that have not been closed yet
+    openLevels int
+}
+
+func (v *opmlExportVisitor) closeUntil(level int) error {
+    for v.openLevels > level {
+        err := v.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "outline"}})
+
+        if nil != err {
+            return err
+        }
+
+        v.openLevels--
+    }
+
+    return nil
+}
+
+func (v *opmlExportVisitor) VisitTitle(m *model, item OItem) error {
+    e := v.encoder
+
+    start := xml.StartElement{
+        Name: xml.Name{Local: "opml"},
+        Attr: []xml.Attr{
+            opmlAttr("version", "2.0"),
+            opmlAttr("xmlns:" + opmlPrefix, opmlNamespace)}}
+
+    head := xml.StartElement{Name: xml.Name{Local: "head"}}
+    titleElem := xml.StartElement{Name: xml.Name{Local: "title"}}
+
+    for _, token := range []xml.Token{
+        start,
+        head,
+        titleElem,
+        xml.CharData(item.GetTxt()),
+        titleElem.End(),
+        head.End(),
+        xml.StartElement{Name: xml.Name{Local: "body"}}} {
+
+        if err := e.EncodeToken(token); nil != err {
+            return err
+        }
+    }
+
+    return nil
+}
+
+func (v *opmlExportVisitor) VisitConfig(m *model, item OItem) error {
+    return nil
+}
+
+func (v *opmlExportVisitor) VisitItem(m *model, item OItem, level int) error {
+    err := v.closeUntil(level - 1)
+
+    if nil != err {
+        return err
+    }
+
+    attrs := []xml.Attr{opmlAttr("text", item.GetTxt())}
+
+    if "" != item.GetBody() {
+        attrs = append(attrs, opmlAttr("_note", item.GetBody()))
+    }
+
+    goutlineAttr := func(name string, value string) {
+        attrs = append(attrs, opmlAttr(opmlPrefix + ":" + name, value))
+    }
+
+    if "" != item.GetId() {
+        goutlineAttr("id", item.GetId())
+    }
+
+    goutlineAttr("created", strconv.FormatInt(item.GetCreated(), 10))
+    goutlineAttr("changed", strconv.FormatInt(item.GetChanged(), 10))
+    goutlineAttr("checked", strconv.FormatBool(item.IsChecked()))
+
+    goutlineAttr("numbered", strconv.FormatBool(item.IsNumbered()))
+    goutlineAttr("expanded", strconv.FormatBool(item.IsExpanded()))
+
+    if props := MetaProperties(item); len(props) > 0 {
+        lines := make([]string, 0, len(props))
+
+        for _, prop := range props {
+            lines = append(lines, formatMetaEntry(prop.Key, prop.Value))
+        }
+
+        goutlineAttr("meta", strings.Join(lines, "\n"))
+    }
+
+    err = v.encoder.EncodeToken(xml.StartElement{Name: xml.Name{Local: "outline"}, Attr: attrs})
+
+    if nil != err {
+        return err
+    }
+
+    v.openLevels = level
+
+    return nil
+}
+
+func (v *opmlExportVisitor) ShouldDescend(m *model, item OItem) bool {
+    return true
+}
+
+func (m model) MarshalOPML() ([]byte, error) {
+    var buf bytes.Buffer
+
+    buf.WriteString(xml.Header)
+
+    v := &opmlExportVisitor{encoder: xml.NewEncoder(&buf)}
+    v.encoder.Indent("", "  ")
+
+    err := m.VisitAll(v)
+
+    if nil == err {
+        err = v.closeUntil(0)
+    }
+
+    if nil == err {
+        err = v.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "body"}})
+    }
+
+    if nil == err {
+        err = v.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "opml"}})
+    }
+
+    if nil == err {
+        err = v.encoder.Flush()
+    }
+
+    if nil != err {
+        return nil, fmt.Errorf("Could not write OPML: %w", err)
+    }
+
+    buf.WriteString("\n")
+
+    return buf.Bytes(), nil
+}
@@ -0,0 +1,119 @@
+package goutlinelib
+
+import (
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestOPMLRoundTrip(t *testing.T) {
+    m := InitialModel()
+    m.SetTitle("Title & more")
+
+    item := m.Title.GetSubs()[0]
+    item.SetTxt("first <item>")
+    item.SetBody("a note\nwith two lines")
+    item.SetChecked(true)
+    SetMetaValue(item, "priority", "A")
+
+    sub := m.AddNewItem(item)
+    sub.SetTxt("sub")
+    item.SetExpanded(true)
+
+    first, err := m.MarshalOPML()
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    var loaded model
+    err = loaded.UnmarshalOPML(first)
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    second, err := loaded.MarshalOPML()
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if string(first) != string(second) {
+        t.Error("Expected", string(first), "after round trip, but got", string(second))
+    }
+
+    res := loaded.Title.GetSubs()[0]
+
+    if res.GetTxt() != "first <item>" || res.GetBody() != "a note\nwith two lines" || !res.IsChecked() || !res.IsExpanded() {
+        t.Error("Unexpected item after round trip", res)
+    }
+
+    if value, _ := MetaValue(res, "priority"); value != "A" {
+        t.Error("Expected", "A", "for meta property, but got", value)
+    }
+
+    if len(res.GetSubs()) != 1 || res.GetSubs()[0].GetTxt() != "sub" {
+        t.Error("Unexpected subs after round trip", res.GetSubs())
+    }
+}
+
+func TestOPMLForeignDocument(t *testing.T) {
+    doc := `<?xml version="1.0"?>
+<opml version="2.0"><head><title>Foreign</title></head>
+<body><outline text="a"><outline text="b" type="link" url="http://example.com"/></outline></body></opml>`
+
+    var m model
+    err := m.UnmarshalOPML([]byte(doc))
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if m.Title.GetTxt() != "Foreign" {
+        t.Error("Expected", "Foreign", "for title, but got", m.Title.GetTxt())
+    }
+
+    if len(m.Title.GetSubs()) != 1 || m.Title.GetSubs()[0].GetSubs()[0].GetTxt() != "b" {
+        t.Error("Unexpected structure", m.Title.GetSubs())
+    }
+
+    if err = m.UnmarshalOPML([]byte(strings.Replace(doc, "</outline></body>", "</body>", 1))); err == nil {
+        t.Error("Expected error for unbalanced document")
+    }
+}
+
+func TestOPMLExportToCurrentFile(t *testing.T) {
+    dir, err := ioutil.TempDir("", "goutline")
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    defer os.RemoveAll(dir)
+
+    filename := filepath.Join(dir, "outline.opml")
+
+    m := InitialModel()
+    m.SetFilename(filename)
+
+    if !m.Save() {
+        t.Fatal("Expected first save to work")
+    }
+
+    m.AddNewItem(m.Title).SetTxt("unsaved edit")
+    m.SetReadOnly(true)
+    m.Export(ExportFilename(m.filename, ".opml"))
+
+    if b, _ := ioutil.ReadFile(filename); strings.Contains(string(b), "unsaved edit") {
+        t.Error("Expected read-only file not to be overwritten by exporting")
+    }
+
+    m.SetReadOnly(false)
+    m.Export(ExportFilename(m.filename, ".opml"))
+
+    if b, _ := ioutil.ReadFile(filename); !strings.Contains(string(b), "unsaved edit") || m.IsDirty() {
+        t.Error("Expected exporting to the current file to save it")
+    }
+
+    if _, err := os.Stat(filename + ".bak"); err != nil {
+        t.Error("Expected backup of the overwritten file, but got", err)
+    }
+}
@@ -0,0 +1,429 @@
+package goutlinelib
+
+import(
+    "bytes"
+    "fmt"
+    "strings"
+    "time"
+    "unicode"
+)
+
+const orgTimestampLayout = "[2006-01-02 Mon 15:04]"
+
+// keys of the PROPERTIES drawer that are mapped to oitem fields; all other
+// properties are kept in the item's meta properties
+const (
+    orgPropId         = "ID"
+    orgPropCreated    = "CREATED"
+    orgPropChanged    = "CHANGED"
+    orgPropVisibility = "VISIBILITY"
+)
+
+func parseOrgHeadline(line string) (level int, keyword string, txt string, ok bool) {
+    for level < len(line) && line[level] == '*' {
+        level++
+    }
+
+    if 0 == level {
+        return
+    }
+
+    if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+        // bold text at the start of a line, not a headline
+        return
+    }
+
+    ok = true
+    txt = strings.TrimSpace(line[level:])
+
+    for _, cur := range []string{"TODO", "DONE"} {
+        if txt == cur || strings.HasPrefix(txt, cur + " ") {
+            keyword = cur
+            txt = strings.TrimSpace(txt[len(cur):])
+            break
+        }
+    }
+
+    return
+}
+
+func isOrgTagChar(r rune) bool {
+    return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_@#%", r)
+}
+
+// splitOrgTags removes the tags at the end of a headline, e.g. ":work:urgent:".
+func splitOrgTags(txt string) (string, []string) {
+    idx := strings.LastIndexAny(txt, " \t")
+    last := txt[idx + 1:]
+
+    if len(last) < 3 || !strings.HasPrefix(last, ":") || !strings.HasSuffix(last, ":") {
+        return txt, nil
+    }
+
+    tags := strings.Split(last[1:len(last) - 1], ":")
+
+    for _, tag := range tags {
+        if "" == tag || -1 != strings.IndexFunc(tag, func(r rune) bool { return !isOrgTagChar(r) }) {
+            return txt, nil
+        }
+    }
+
+    return strings.TrimSpace(txt[:idx + 1]), tags
+}
+
+// orgTags returns the headline tags of an item (without those at the end of
+// its text), or false if its tags cannot be written as headline tags (they are
+// kept as a property then).
+func orgTags(item OItem) (string, bool) {
+    tags := unwrittenTags(item)
+
+    for _, tag := range tags {
+        if -1 != strings.IndexFunc(tag, func(r rune) bool { return !isOrgTagChar(r) }) {
+            return "", false
+        }
+    }
+
+    if 0 == len(tags) {
+        return "", true
+    }
+
+    return ":" + strings.Join(tags, ":") + ":", true
+}
+
+func isOrgPlanningLine(line string) bool {
+    trimmed := strings.TrimSpace(line)
+
+    for _, cur := range []string{"SCHEDULED:", "DEADLINE:", "CLOSED:"} {
+        if strings.HasPrefix(trimmed, cur) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func isOrgDrawerLine(line string, name string) bool {
+    return strings.EqualFold(strings.TrimSpace(line), ":" + name + ":")
+}
+
+func parseOrgProperty(line string) (key string, value string, ok bool) {
+    trimmed := strings.TrimSpace(line)
+
+    if !strings.HasPrefix(trimmed, ":") {
+        return
+    }
+
+    end := strings.Index(trimmed[1:], ":")
+
+    if end <= 0 {
+        return
+    }
+
+    key = trimmed[1:end + 1]
+    value = strings.TrimSpace(trimmed[end + 2:])
+    ok = true
+
+    return
+}
+
+func parseOrgTimestamp(value string) (int64, error) {
+    fields := strings.Fields(strings.Trim(value, "[]<>"))
+
+    if 0 == len(fields) {
+        return 0, fmt.Errorf("Empty timestamp")
+    }
+
+    t, err := time.ParseInLocation("2006-01-02", fields[0], time.Local)
+
+    if err != nil {
+        return 0, fmt.Errorf("Invalid timestamp %s: %w", value, err)
+    }
+
+    for _, field := range fields[1:] {
+        if clock, err := time.Parse("15:04", field); err == nil {
+            t = t.Add(time.Duration(clock.Hour()) * time.Hour + time.Duration(clock.Minute()) * time.Minute)
+        }
+    }
+
+    return t.UTC().Unix(), nil
+}
+
+func formatOrgTimestamp(ts int64) string {
+    return time.Unix(ts, 0).Local().Format(orgTimestampLayout)
+}
+
+// joinOrgLines keeps every line terminated, so that a body consisting of a
+// single blank line survives a round trip
+func joinOrgLines(lines []string) string {
+    var result strings.Builder
+
+    for _, line := range lines {
+        result.WriteString(line)
+        result.WriteString("\n")
+    }
+
+    return result.String()
+}
+
+func applyOrgProperty(item *oitem, key string, value string) {
+    switch strings.ToUpper(key) {
+
+    case orgPropId:
+        item.Id = value
+        return
+
+    case orgPropCreated, orgPropChanged:
+        ts, err := parseOrgTimestamp(value)
+
+        if err == nil {
+            if strings.EqualFold(key, orgPropCreated) {
+                item.Created = ts
+            } else {
+                item.Changed = ts
+            }
+
+            return
+        }
+
+    case orgPropVisibility:
+        item.Expanded = !strings.EqualFold(value, "folded")
+        return
+    }
+
+    SetMetaValue(item, key, value)
+}
+
+// UnmarshalOrg builds the outline from an org-mode document: headline depth
+// gives the nesting, "#+TITLE:" the title. Text between headlines and
+// drawers other than PROPERTIES are kept verbatim in the item bodies.
+func (m *model) UnmarshalOrg(data []byte) (err error) {
+    title := &oitem{Type: "oitem"}
+
+    lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+    if len(lines) > 0 && "" == lines[len(lines) - 1] {
+        lines = lines[:len(lines) - 1]
+    }
+
+    // currently open headlines; index is the headline level
+    open := []OItem{title}
+    var cur OItem = title
+    var body []string
+
+    for i := 0; i < len(lines); i++ {
+        line := lines[i]
+        level, keyword, txt, ok := parseOrgHeadline(line)
+
+        if !ok {
+            if cur == title && strings.HasPrefix(strings.ToUpper(line), "#+TITLE:") {
+                title.Txt = strings.TrimSpace(line[len("#+TITLE:"):])
+                m.orgTitleLine = i + 1
+            } else {
+                body = append(body, line)
+            }
+
+            continue
+        }
+
+        cur.SetBody(joinOrgLines(body))
+        body = nil
+
+        txt, tags := splitOrgTags(txt)
+        item := &oitem{Type: "oitem", Txt: txt}
+        SetItemTags(item, tags)
+
+        if "" != keyword {
+            item.Checked = "DONE" == keyword
+            SetMetaValue(item, metaTodo, keyword)
+        }
+
+        next := i + 1
+
+        if next < len(lines) && isOrgPlanningLine(lines[next]) {
+            // stays in the body, but is written before the properties again
+            body = append(body, lines[next])
+            next++
+        }
+
+        if next < len(lines) && isOrgDrawerLine(lines[next], "PROPERTIES") {
+            end := next + 1
+
+            for end < len(lines) && !isOrgDrawerLine(lines[end], "END") {
+                end++
+            }
+
+            // an unterminated drawer is not a drawer, so leave it in the body
+            if end < len(lines) {
+                for _, prop := range lines[next + 1:end] {
+                    if key, value, ok := parseOrgProperty(prop); ok {
+                        applyOrgProperty(item, key, value)
+                    }
+                }
+
+                next = end + 1
+            }
+        }
+
+        for len(open) > level {
+            open = open[:len(open) - 1]
+        }
+
+        parent := open[len(open) - 1]
+        parent.AddSubAt(item, len(parent.GetSubs()))
+        open = append(open, item)
+
+        cur = item
+        i = next - 1
+    }
+
+    cur.SetBody(joinOrgLines(body))
+
+    if !title.HasSubs() {
+        title.AddSubAt(&oitem{Type: "oitem"}, 0)
+    }
+
+    m.Title = title
+    m.Config = &oitem{}
+    m.Cursor = 0
+
+    return
+}
+
+type orgExportVisitor struct {
+    buf bytes.Buffer
+}
+
+func (v *orgExportVisitor) writeBody(body string) {
+    v.buf.WriteString(body)
+
+    if "" != body && !strings.HasSuffix(body, "\n") {
+        v.buf.WriteString("\n")
+    }
+}
+
+func (v *orgExportVisitor) VisitTitle(m *model, item OItem) error {
+    // documents without a title stay without one
+    if "" == item.GetTxt() && 0 == m.orgTitleLine {
+        v.writeBody(item.GetBody())
+        return nil
+    }
+
+    // the title line goes back between the header lines it was read from
+    lines := strings.SplitAfter(item.GetBody(), "\n")
+    before := 0
+
+    if m.orgTitleLine > 1 {
+        before = m.orgTitleLine - 1
+    }
+
+    if before > len(lines) {
+        before = len(lines)
+    }
+
+    v.writeBody(strings.Join(lines[:before], ""))
+
+    if "" != item.GetTxt() {
+        fmt.Fprintf(&v.buf, "#+TITLE: %s\n", item.GetTxt())
+    } else {
+        v.buf.WriteString("#+TITLE:\n")
+    }
+
+    v.writeBody(strings.Join(lines[before:], ""))
+
+    return nil
+}
+
+func (v *orgExportVisitor) VisitConfig(m *model, item OItem) error {
+    return nil
+}
+
+func (v *orgExportVisitor) VisitItem(m *model, item OItem, level int) error {
+    v.buf.WriteString(strings.Repeat("*", level))
+
+    keyword, isTask := MetaValue(item, metaTodo)
+
+    if item.IsChecked() {
+        v.buf.WriteString(" DONE")
+    } else if isTask && "" != keyword {
+        v.buf.WriteString(" TODO")
+    }
+
+    if "" != item.GetTxt() {
+        v.buf.WriteString(" " + item.GetTxt())
+    }
+
+    tags, headlineTags := orgTags(item)
+
+    if "" != tags {
+        v.buf.WriteString(" " + tags)
+    }
+
+    v.buf.WriteString("\n")
+
+    body := item.GetBody()
+    lines := strings.SplitN(body, "\n", 2)
+
+    if isOrgPlanningLine(lines[0]) {
+        v.buf.WriteString(lines[0] + "\n")
+        body = ""
+
+        if len(lines) > 1 {
+            body = lines[1]
+        }
+    }
+
+    var props []MetaProperty
+
+    if "" != item.GetId() {
+        props = append(props, MetaProperty{Key: orgPropId, Value: item.GetId()})
+    }
+
+    if 0 != item.GetCreated() {
+        props = append(props, MetaProperty{Key: orgPropCreated, Value: formatOrgTimestamp(item.GetCreated())})
+    }
+
+    if 0 != item.GetChanged() {
+        props = append(props, MetaProperty{Key: orgPropChanged, Value: formatOrgTimestamp(item.GetChanged())})
+    }
+
+    if item.IsExpanded() && item.HasSubs() {
+        props = append(props, MetaProperty{Key: orgPropVisibility, Value: "children"})
+    }
+
+    for _, prop := range MetaProperties(item) {
+        if strings.EqualFold(prop.Key, metaTodo) || (headlineTags && strings.EqualFold(prop.Key, metaTags)) {
+            continue
+        }
+
+        props = append(props, prop)
+    }
+
+    if len(props) > 0 {
+        v.buf.WriteString(":PROPERTIES:\n")
+
+        for _, prop := range props {
+            fmt.Fprintf(&v.buf, ":%s: %s\n", prop.Key, prop.Value)
+        }
+
+        v.buf.WriteString(":END:\n")
+    }
+
+    v.writeBody(body)
+
+    return nil
+}
+
+func (v *orgExportVisitor) ShouldDescend(m *model, item OItem) bool {
+    return true
+}
+
+func (m model) MarshalOrg() ([]byte, error) {
+    v := &orgExportVisitor{}
+    err := m.VisitAll(v)
+
+    if nil != err {
+        return nil, err
+    }
+
+    return v.buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+const orgRoundTripDoc = `#+TITLE: Notes
+Some preamble text
+
+* TODO First
+SCHEDULED: <2022-05-10 Tue>
+:PROPERTIES:
+:ID: abc
+:CREATED: [2022-05-03 Tue 10:15]
+:VISIBILITY: children
+:CUSTOM: keep me
+:END:
+:LOGBOOK:
+- State "DONE" from "TODO" [2022-05-04 Wed 09:00]
+:END:
+body of first
+
+** DONE Sub
+** Plain sub
+*bold* body line
+* Second
+`
+
+func TestOrgRoundTrip(t *testing.T) {
+    var m model
+
+    err := m.UnmarshalOrg([]byte(orgRoundTripDoc))
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    res, err := m.MarshalOrg()
+    if err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if string(res) != orgRoundTripDoc {
+        t.Error("Expected", orgRoundTripDoc, "after round trip, but got", string(res))
+    }
+}
+
+func TestOrgMapping(t *testing.T) {
+    var m model
+    m.UnmarshalOrg([]byte(orgRoundTripDoc))
+
+    if m.Title.GetTxt() != "Notes" {
+        t.Error("Expected", "Notes", "for title, but got", m.Title.GetTxt())
+    }
+
+    if len(m.Title.GetSubs()) != 2 {
+        t.Fatal("Expected", 2, "top level items, but got", len(m.Title.GetSubs()))
+    }
+
+    first := m.Title.GetSubs()[0]
+
+    if first.GetId() != "abc" || !first.IsExpanded() || first.IsChecked() {
+        t.Error("Unexpected mapping of first item", first)
+    }
+
+    if value, _ := MetaValue(first, "CUSTOM"); value != "keep me" {
+        t.Error("Expected", "keep me", "for unknown property, but got", value)
+    }
+
+    subs := first.GetSubs()
+
+    if len(subs) != 2 || !subs[0].IsChecked() || subs[1].IsChecked() {
+        t.Error("Unexpected subs of first item", subs)
+    }
+}
+
+func TestOrgTitleLine(t *testing.T) {
+    for _, doc := range []string{"* First\n* Second\n", "#+TITLE:\n* First\n", "#+AUTHOR: me\n#+STARTUP: overview\n#+TITLE: Notes\n\nIntro\n* First\n"} {
+        var m model
+
+        if err := m.UnmarshalOrg([]byte(doc)); err != nil {
+            t.Fatal("Unexpected error", err)
+        }
+
+        if res, _ := m.MarshalOrg(); string(res) != doc {
+            t.Error("Expected", doc, "after round trip, but got", string(res))
+        }
+    }
+}
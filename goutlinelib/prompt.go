@@ -0,0 +1,71 @@
+package goutlinelib
+
+import(
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// a single line of input requested in the footer, e.g. a file name
+type prompt struct {
+    label string
+
+    // called with the entered text when confirmed with enter
+    onConfirm func(m *model, value string)
+
+    // optional: called after every change of the text, when cancelled, and
+    // for every key before the text input sees it (returns whether the key
+    // has been handled)
+    onChange func(m *model, value string)
+    onCancel func(m *model)
+    onKey func(m *model, key string) bool
+}
+
+// OpenPrompt asks for a line of text, starting with value.
+func (m *model) OpenPrompt(label string, value string, onConfirm func(m *model, value string)) {
+    m.prompt = &prompt{label: label, onConfirm: onConfirm}
+    m.textinput.SetValue(value)
+    m.textinput.CursorEnd()
+}
+
+func (m *model) handlePromptKey(msg tea.Msg) {
+    p := m.prompt
+
+    if msg, ok := msg.(tea.KeyMsg); ok {
+        if nil != p.onKey && p.onKey(m, msg.String()) {
+            return
+        }
+
+        switch msg.String() {
+
+        case "esc", "ctrl+c":
+            m.prompt = nil
+
+            if nil != p.onCancel {
+                p.onCancel(m)
+            }
+
+            return
+
+        case "enter":
+            m.prompt = nil
+            p.onConfirm(m, m.textinput.Value())
+            return
+        }
+    }
+
+    before := m.textinput.Value()
+    m.textinput, _ = m.textinput.Update(msg)
+
+    if nil != p.onChange && before != m.textinput.Value() {
+        p.onChange(m, m.textinput.Value())
+    }
+}
+
+func (m model) promptView() string {
+    s := m.prompt.label + m.textinput.View()
+
+    if "" != m.status {
+        s += "   " + m.status
+    }
+
+    return s + "\n"
+}
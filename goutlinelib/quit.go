@@ -0,0 +1,58 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "os"
+
+    tea "github.com/charmbracelet/bubbletea"
+)
+
+// RequestQuit quits right away if there are no unsaved changes, and asks what
+// to do with them otherwise.
+func (m *model) RequestQuit() tea.Cmd {
+    if !m.IsDirty() {
+        return tea.Quit
+    }
+
+    m.confirmingQuit = true
+
+    return nil
+}
+
+func (m *model) handleQuitKey(msg tea.KeyMsg) tea.Cmd {
+    switch msg.String() {
+
+    case "s", "y":
+        m.confirmingQuit = false
+
+        if m.Save() {
+            return tea.Quit
+        }
+
+        // Save has told why
+        m.status += "; not quitting"
+
+    case "d", "n":
+        // the changes are unwanted, so there is nothing to recover later on
+        os.Remove(JournalFilename(m.filename))
+
+        return tea.Quit
+
+    case "esc", "c", "ctrl+c":
+        m.confirmingQuit = false
+    }
+
+    return nil
+}
+
+func (m model) quitPromptView() string {
+    s := headerStyle.Render(fmt.Sprintf("%s [%s] [+]", m.Title.GetTxt(), m.filename)) + "\n\n"
+    s += fmt.Sprintf("%s has unsaved changes.\n\n", m.filename)
+    s += "s: save and quit, d: discard changes and quit, esc: cancel\n"
+
+    if "" != m.status {
+        s += "\n" + m.status + "\n"
+    }
+
+    return s
+}
@@ -0,0 +1,222 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "strings"
+
+    "github.com/charmbracelet/lipgloss"
+)
+
+type search struct {
+    query string
+
+    // matching items in outline order
+    matches []OItem
+    current int
+
+    // expansion state of all items when the search was started
+    expanded map[OItem]bool
+
+    // item under the cursor when the search was started
+    origin OItem
+}
+
+// walkStored calls fn for all items below (and including) item whose place in
+// the outline is stored, i.e. not for the subs of transclusions and external
+// items.
+func walkStored(item OItem, fn func(item OItem)) {
+    fn(item)
+
+    if !storesSubs(item) {
+        return
+    }
+
+    for _, sub := range item.GetSubs() {
+        walkStored(sub, fn)
+    }
+}
+
+func containsFold(s string, substr string) bool {
+    return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func (m *model) saveExpanded() map[OItem]bool {
+    result := make(map[OItem]bool)
+
+    walkStored(m.Title, func(item OItem) {
+        result[item] = item.IsExpanded()
+    })
+
+    return result
+}
+
+func (m *model) restoreExpanded(expanded map[OItem]bool) {
+    for item, value := range expanded {
+        item.SetExpanded(value)
+    }
+
+    m.UpdateLinearizedMapping()
+}
+
+// findMatches returns all items containing query, ignoring case.
+func (m *model) findMatches(query string) []OItem {
+    var result []OItem
+
+    walkStored(m.Title, func(item OItem) {
+        // transclusions would only repeat their target
+        if _, ok := item.(*oitemproxy); ok || item == m.Title {
+            return
+        }
+
+        if containsFold(item.GetTxt(), query) {
+            result = append(result, item)
+        }
+    })
+
+    return result
+}
+
+// StartSearch opens the search prompt; matches are revealed while typing.
+func (m *model) StartSearch() {
+    m.search = &search{expanded: m.saveExpanded(), origin: m.linearized[m.Cursor]}
+
+    m.prompt = &prompt{
+        label: "/",
+        onConfirm: func(m *model, query string) {
+            m.finishSearch()
+        },
+        onChange: func(m *model, query string) {
+            m.updateSearch(query)
+        },
+        onCancel: func(m *model) {
+            m.restoreExpanded(m.search.expanded)
+
+            if pos := m.PosInLinearized(m.search.origin); -1 != pos {
+                m.Cursor = pos
+            }
+
+            m.search = nil
+            m.status = ""
+        },
+        onKey: func(m *model, key string) bool {
+            switch key {
+
+            case "down", "tab", "ctrl+n":
+                m.nextMatch(1)
+                return true
+
+            case "up", "shift+tab", "ctrl+p":
+                m.nextMatch(-1)
+                return true
+            }
+
+            return false
+        },
+    }
+
+    m.textinput.SetValue("")
+}
+
+// updateSearch reveals all matches of query; everything else is shown as it
+// was before the search.
+func (m *model) updateSearch(query string) {
+    m.restoreExpanded(m.search.expanded)
+
+    m.search.query = query
+    m.search.matches = nil
+    m.search.current = 0
+    m.status = ""
+
+    if "" == query {
+        return
+    }
+
+    m.search.matches = m.findMatches(query)
+
+    for _, item := range m.search.matches {
+        for cur := item.GetParent(); nil != cur; cur = cur.GetParent() {
+            cur.SetExpanded(true)
+        }
+    }
+
+    m.UpdateLinearizedMapping()
+    m.showMatch()
+}
+
+func (m *model) showMatch() {
+    if 0 == len(m.search.matches) {
+        m.status = "no matches"
+        return
+    }
+
+    m.RevealItem(m.search.matches[m.search.current])
+    m.status = fmt.Sprintf("match %d/%d", m.search.current + 1, len(m.search.matches))
+}
+
+// nextMatch moves to the next (or, with a negative direction, previous) match
+// that is still part of the outline.
+func (m *model) nextMatch(direction int) {
+    if nil == m.search || 0 == len(m.search.matches) {
+        m.status = "no search"
+        return
+    }
+
+    for range m.search.matches {
+        m.search.current = (m.search.current + direction + len(m.search.matches)) % len(m.search.matches)
+
+        if match := m.search.matches[m.search.current]; m.Title.HasSub(match) {
+            m.showMatch()
+            return
+        }
+    }
+
+    m.status = "no matches"
+}
+
+// finishSearch keeps only the path to the chosen match expanded; the matches
+// stay highlighted for n and N.
+func (m *model) finishSearch() {
+    m.restoreExpanded(m.search.expanded)
+
+    if 0 == len(m.search.matches) {
+        if pos := m.PosInLinearized(m.search.origin); -1 != pos {
+            m.Cursor = pos
+        }
+
+        m.search = nil
+
+        return
+    }
+
+    m.showMatch()
+}
+
+// highlightMatches renders txt with style, and all occurrences of query in
+// addition highlighted.
+func highlightMatches(txt string, query string, style lipgloss.Style) string {
+    lower := strings.ToLower(txt)
+    lowerQuery := strings.ToLower(query)
+
+    // offsets would not fit the original text
+    if "" == query || len(lower) != len(txt) || len(lowerQuery) != len(query) {
+        return style.Render(txt)
+    }
+
+    highlight := style.Copy().Background(lipgloss.Color("227")).Foreground(lipgloss.Color("0"))
+
+    result := ""
+
+    for {
+        idx := strings.Index(lower, lowerQuery)
+
+        if -1 == idx {
+            break
+        }
+
+        result += style.Render(txt[:idx]) + highlight.Render(txt[idx:idx + len(query)])
+        txt = txt[idx + len(query):]
+        lower = lower[idx + len(query):]
+    }
+
+    return result + style.Render(txt)
+}
@@ -0,0 +1,68 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestSearchRevealsMatchesAndRestoresOnCancel(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "alpha", "Subs": [{"Subs": [{"Txt": "deep Alpha"}]}]}, {"Txt": "beta", "Subs": [{"Txt": "another alpha"}]}]}}`))
+    a, b := m.Title.GetSubs()[0], m.Title.GetSubs()[1]
+    deep, other := a.GetSubs()[0].GetSubs()[0], b.GetSubs()[0]
+    m.Cursor = m.PosInLinearized(b)
+
+    m.StartSearch()
+    m.updateSearch("ALPHA")
+
+    if len(m.search.matches) != 3 || m.search.matches[1] != deep {
+        t.Fatal("Expected matches in the whole tree, but got", m.search.matches)
+    }
+
+    if -1 == m.PosInLinearized(deep) || -1 == m.PosInLinearized(other) {
+        t.Error("Expected all matches to be revealed")
+    }
+
+    m.nextMatch(1)
+
+    if m.linearized[m.Cursor] != deep {
+        t.Error("Expected cursor on the second match")
+    }
+
+    m.prompt.onCancel(&m)
+
+    if a.IsExpanded() || -1 != m.PosInLinearized(deep) || m.linearized[m.Cursor].GetTxt() != "beta" {
+        t.Error("Expected expansion state and cursor to be restored")
+    }
+}
+
+func TestSearchConfirmKeepsPathToChosenMatch(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "alpha", "Subs": [{"Subs": [{"Txt": "deep Alpha"}]}]}, {"Txt": "beta", "Subs": [{"Txt": "another alpha"}]}]}}`))
+    a, b := m.Title.GetSubs()[0], m.Title.GetSubs()[1]
+    deep, other := a.GetSubs()[0].GetSubs()[0], b.GetSubs()[0]
+    m.Cursor = m.PosInLinearized(b)
+
+    m.StartSearch()
+    m.updateSearch("alpha")
+    m.nextMatch(1)
+    m.prompt.onConfirm(&m, "alpha")
+
+    if !a.IsExpanded() || !deep.GetParent().IsExpanded() || m.linearized[m.Cursor] != deep {
+        t.Error("Expected path to the chosen match to stay expanded")
+    }
+
+    if -1 != m.PosInLinearized(other) {
+        t.Error("Expected other matches to be hidden again")
+    }
+
+    m.nextMatch(1)
+
+    if m.linearized[m.Cursor] != other {
+        t.Error("Expected n to go to the next match")
+    }
+
+    m.nextMatch(1)
+    m.nextMatch(-1)
+
+    if m.linearized[m.Cursor] != other {
+        t.Error("Expected N to go to the previous match")
+    }
+}
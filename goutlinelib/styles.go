@@ -0,0 +1,25 @@
+package goutlinelib
+
+import(
+    "github.com/charmbracelet/lipgloss"
+)
+
+// styles of the views shown instead of the outline, e.g. lists and prompts
+var (
+    colorYellow = lipgloss.Color("227")
+
+    // first line, like the header of the outline
+    headerStyle = lipgloss.NewStyle().Background(colorYellow).Foreground(lipgloss.Color("0"))
+
+    // entry under the cursor
+    selectedStyle = lipgloss.NewStyle().Background(lipgloss.Color("63")).Foreground(lipgloss.Color("255"))
+)
+
+// listLine returns an entry of a list view, marked if it is under the cursor.
+func listLine(line string, selected bool) string {
+    if selected {
+        return "> " + selectedStyle.Render(line) + " <\n"
+    }
+
+    return "  " + line + "\n"
+}
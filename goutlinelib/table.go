@@ -0,0 +1,407 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/charmbracelet/lipgloss"
+)
+
+// columns every table has; the others are the meta properties of the rows
+const (
+    tableColumnText = "text"
+    tableColumnChecked = "checked"
+    tableColumnCreated = "created"
+    tableColumnChanged = "changed"
+)
+
+const tableTimestampLayout = "2006-01-02 15:04"
+
+// cells are cut off after this many characters
+const tableMaxCellWidth = 30
+
+// shown instead of the outline: the subs of an item as rows of a table
+type tableView struct {
+    parent OItem
+
+    // sorting only changes the order in the table, not in the outline
+    sortColumn string
+    sortDescending bool
+
+    row int
+    column int
+}
+
+// OpenTable shows the subs of item as a table.
+func (m *model) OpenTable(item OItem) {
+    if 0 == len(item.GetSubs()) {
+        m.status = "no subs to show as a table"
+        return
+    }
+
+    m.table = &tableView{parent: item}
+}
+
+func isBuiltinTableColumn(column string) bool {
+    switch column {
+
+    case tableColumnText, tableColumnChecked, tableColumnCreated, tableColumnChanged:
+        return true
+    }
+
+    return false
+}
+
+// columns returns the builtin columns and the keys of the meta
+// properties of all rows, in the order they first appear.
+func (t *tableView) columns() []string {
+    result := []string{tableColumnText, tableColumnChecked}
+    var keys []string
+    seen := make(map[string]bool)
+
+    for _, row := range t.parent.GetSubs() {
+        for _, prop := range MetaProperties(row) {
+            key := strings.ToLower(prop.Key)
+
+            // properties named like builtin columns can only be edited as
+            // properties; the task flag is shown as the checked column
+            if seen[key] || isBuiltinTableColumn(key) || strings.EqualFold(key, metaTodo) {
+                continue
+            }
+
+            seen[key] = true
+            keys = append(keys, prop.Key)
+        }
+    }
+
+    result = append(result, keys...)
+
+    return append(result, tableColumnCreated, tableColumnChanged)
+}
+
+func formatTableTimestamp(ts int64) string {
+    if 0 == ts {
+        return ""
+    }
+
+    return time.Unix(ts, 0).Local().Format(tableTimestampLayout)
+}
+
+func tableCell(item OItem, column string) string {
+    switch column {
+
+    case tableColumnText:
+        return item.GetTxt()
+
+    case tableColumnChecked:
+        if item.IsChecked() {
+            return "✓"
+        }
+
+        if IsTask(item) {
+            return "☐"
+        }
+
+        return ""
+
+    case tableColumnCreated:
+        return formatTableTimestamp(item.GetCreated())
+
+    case tableColumnChanged:
+        return formatTableTimestamp(item.GetChanged())
+    }
+
+    value, _ := MetaValue(item, column)
+
+    return value
+}
+
+// lessTableCells compares numbers as numbers and everything else ignoring
+// case; empty cells come last.
+func lessTableCells(a OItem, b OItem, column string) bool {
+    switch column {
+
+    case tableColumnCreated:
+        return a.GetCreated() < b.GetCreated()
+
+    case tableColumnChanged:
+        return a.GetChanged() < b.GetChanged()
+    }
+
+    x, y := tableCell(a, column), tableCell(b, column)
+
+    if "" == x || "" == y {
+        return "" != x
+    }
+
+    if xNumber, ok := parseMetaNumber(x); ok {
+        if yNumber, ok := parseMetaNumber(y); ok {
+            return xNumber < yNumber
+        }
+    }
+
+    return strings.ToLower(x) < strings.ToLower(y)
+}
+
+// rows returns the subs of the table's item in the order they are shown.
+func (t *tableView) rows() []OItem {
+    rows := append([]OItem(nil), t.parent.GetSubs()...)
+
+    if "" != t.sortColumn {
+        sort.SliceStable(rows, func(i, j int) bool {
+            if t.sortDescending {
+                return lessTableCells(rows[j], rows[i], t.sortColumn)
+            }
+
+            return lessTableCells(rows[i], rows[j], t.sortColumn)
+        })
+    }
+
+    return rows
+}
+
+// SortTable sorts by the column under the cursor; sorting by the same column
+// again reverses the order.
+func (m *model) SortTable() {
+    t := m.table
+    column := t.columns()[t.column]
+    cur := t.rows()[t.row]
+
+    if t.sortColumn == column {
+        t.sortDescending = !t.sortDescending
+    } else {
+        t.sortColumn = column
+        t.sortDescending = false
+    }
+
+    for i, row := range t.rows() {
+        if row == cur {
+            t.row = i
+        }
+    }
+}
+
+// SetTableCell changes the cell under the cursor (toggles it for the checked
+// column); an empty value removes a property. The change can be undone. The
+// cells of links are read-only.
+func (m *model) SetTableCell(value string) {
+    t := m.table
+    column := t.columns()[t.column]
+    item := t.rows()[t.row]
+
+    if m.IsGenerated(item) {
+        m.status = "generated from an external source (r: refresh)"
+        return
+    }
+
+    if !canHaveMeta(item) {
+        m.status = "links show their target and cannot be edited"
+        return
+    }
+
+    switch column {
+
+    case tableColumnCreated, tableColumnChanged:
+        m.status = "timestamps cannot be edited"
+        return
+
+    case tableColumnChecked:
+        m.ToggleChecked(item)
+        return
+    }
+
+    if tableCell(item, column) == value {
+        return
+    }
+
+    m.PushUndo()
+
+    switch {
+
+    case tableColumnText == column:
+        item.SetTxt(value)
+
+    case "" == value:
+        DeleteMetaValue(item, column)
+
+    default:
+        SetMetaValue(item, column, value)
+    }
+
+    item.SetTimestampChangedNow()
+    m.MarkDirty()
+    m.UpdateLinearizedMapping()
+}
+
+func (m *model) EditTableCell() {
+    t := m.table
+    column := t.columns()[t.column]
+
+    if tableColumnChecked == column || !canHaveMeta(t.rows()[t.row]) {
+        m.SetTableCell("")
+        return
+    }
+
+    m.OpenPrompt(column + ": ", tableCell(t.rows()[t.row], column), func(m *model, value string) {
+        m.SetTableCell(value)
+    })
+}
+
+// handleTableKey returns whether the key is left to the viewport, which
+// scrolls on up and down as in the outline.
+func (m *model) handleTableKey(msg tea.KeyMsg) bool {
+    t := m.table
+    t.clamp()
+
+    m.status = ""
+
+    // e.g. the output of a command has become empty
+    if 0 == len(t.parent.GetSubs()) {
+        m.table = nil
+        return false
+    }
+
+    switch msg.String() {
+
+    case "up", "k":
+        if t.row > 0 {
+            t.row--
+        }
+
+        return true
+
+    case "down", "j":
+        if t.row < len(t.parent.GetSubs()) - 1 {
+            t.row++
+        }
+
+        return true
+
+    case "left", "h":
+        if t.column > 0 {
+            t.column--
+        }
+
+    case "right", "l":
+        if t.column < len(t.columns()) - 1 {
+            t.column++
+        }
+
+    case "s":
+        m.SortTable()
+
+    case "enter", "i", " ":
+        m.EditTableCell()
+
+    case "esc", "q", "V":
+        m.table = nil
+    }
+
+    return false
+}
+
+// clamp keeps the cursor within the table, e.g. after rows or columns
+// have been removed.
+func (t *tableView) clamp() {
+    if rows := len(t.parent.GetSubs()); t.row >= rows {
+        t.row = rows - 1
+    }
+
+    if columns := len(t.columns()); t.column >= columns {
+        t.column = columns - 1
+    }
+
+    if t.row < 0 {
+        t.row = 0
+    }
+}
+
+func fitTableCell(s string, width int) string {
+    runes := []rune(s)
+
+    if len(runes) > width {
+        return string(runes[:width - 1]) + "…"
+    }
+
+    return s + strings.Repeat(" ", width - len(runes))
+}
+
+func (m model) tableViewString() string {
+    t := m.table
+    t.clamp()
+
+    column_style := lipgloss.NewStyle().Bold(true)
+
+    columns := t.columns()
+    rows := t.rows()
+
+    headers := make([]string, len(columns))
+    widths := make([]int, len(columns))
+
+    for i, column := range columns {
+        headers[i] = column
+
+        if column == t.sortColumn {
+            if t.sortDescending {
+                headers[i] += " ▼"
+            } else {
+                headers[i] += " ▲"
+            }
+        }
+
+        widths[i] = len([]rune(headers[i]))
+
+        for _, row := range rows {
+            if width := len([]rune(tableCell(row, column))); width > widths[i] {
+                widths[i] = width
+            }
+        }
+
+        if widths[i] > tableMaxCellWidth {
+            widths[i] = tableMaxCellWidth
+        }
+    }
+
+    s := headerStyle.Render(fmt.Sprintf("Subs of %q", t.parent.GetTxt())) + "\n\n"
+
+    s += "  "
+
+    for i := range columns {
+        s += column_style.Render(fitTableCell(headers[i], widths[i])) + " │ "
+    }
+
+    s += "\n"
+
+    for i, row := range rows {
+        if i == t.row {
+            s += "> "
+        } else {
+            s += "  "
+        }
+
+        for j, column := range columns {
+            cell := fitTableCell(tableCell(row, column), widths[j])
+
+            if i == t.row && j == t.column {
+                cell = selectedStyle.Render(cell)
+            }
+
+            s += cell + " │ "
+        }
+
+        s += "\n"
+    }
+
+    s += "\nh/l: column   s: sort   enter: edit   V, esc: back to the outline\n"
+
+    if nil != m.prompt {
+        s += m.promptView()
+    } else if "" != m.status {
+        s += m.status + "\n"
+    }
+
+    return s
+}
@@ -0,0 +1,119 @@
+package goutlinelib
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestTableColumnsAndSorting(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "estimates", "Subs": [
+        {"Txt": "b", "Meta": {"Subs": [{"Txt": "estimate = 10"}, {"Txt": "context = home"}]}},
+        {"Txt": "a", "Meta": {"Subs": [{"Txt": "estimate = 9"}]}},
+        {"Txt": "c", "Meta": {"Subs": [{"Txt": "estimate = 100"}, {"Txt": "context = Work"}]}}]}]}}`))
+    parent := m.Title.GetSubs()[0]
+
+    m.OpenTable(parent)
+
+    if columns := strings.Join(m.table.columns(), "|"); columns != "text|checked|estimate|context|created|changed" {
+        t.Error("Unexpected columns", columns)
+    }
+
+    texts := func() string {
+        var result []string
+
+        for _, row := range m.table.rows() {
+            result = append(result, row.GetTxt())
+        }
+
+        return strings.Join(result, "")
+    }
+
+    m.table.column = 2
+    m.SortTable()
+
+    if order := texts(); order != "abc" || m.table.row != 1 {
+        t.Error("Expected numeric order with the cursor staying on its row, but got", order, m.table.row)
+    }
+
+    m.SortTable()
+
+    if order := texts(); order != "cba" {
+        t.Error("Expected reversed order, but got", order)
+    }
+
+    m.table.column = 3
+    m.SortTable()
+
+    if order := texts(); order != "bca" {
+        t.Error("Expected empty cells last, but got", order)
+    }
+
+    if order := parent.GetSubs()[0].GetTxt() + parent.GetSubs()[1].GetTxt(); order != "ba" {
+        t.Error("Expected the outline not to be sorted, but got", order)
+    }
+}
+
+func TestTableEditing(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "estimates", "Subs": [
+        {"Txt": "b", "Meta": {"Subs": [{"Txt": "estimate = 10"}, {"Txt": "context = home"}]}},
+        {"Txt": "a", "Meta": {"Subs": [{"Txt": "estimate = 9"}]}},
+        {"Txt": "c", "Meta": {"Subs": [{"Txt": "estimate = 100"}, {"Txt": "context = Work"}]}}]}]}}`))
+    parent := m.Title.GetSubs()[0]
+
+    m.OpenTable(parent)
+    m.table.row = 1
+    m.table.column = 3
+    m.SetTableCell("office")
+
+    if value, _ := MetaValue(parent.GetSubs()[1], "context"); value != "office" || !m.IsDirty() {
+        t.Error("Expected property to be added, but got", value)
+    }
+
+    m.table.column = 0
+    m.SetTableCell("renamed")
+    m.table.column = 1
+    m.EditTableCell()
+
+    if item := parent.GetSubs()[1]; item.GetTxt() != "renamed" || !item.IsChecked() {
+        t.Error("Expected text to be changed and item to be checked, but got", item.GetTxt(), item.IsChecked())
+    }
+
+    m.table.column = 4
+    m.SetTableCell("2020-01-01 00:00")
+
+    if "" == m.status {
+        t.Error("Expected timestamps not to be editable")
+    }
+
+    m.PopUndo()
+    m.PopUndo()
+
+    if item := m.Title.GetSubs()[0].GetSubs()[1]; item.GetTxt() != "a" || item.IsChecked() {
+        t.Error("Expected changes to be undone, but got", item.GetTxt(), item.IsChecked())
+    }
+}
+
+func TestTableLinkRowsAreReadOnly(t *testing.T) {
+    m := loadFromJSON(t, []byte(`{"Title": {"Subs": [{"Txt": "estimates", "Subs": [
+        {"Txt": "b", "Meta": {"Subs": [{"Txt": "estimate = 10"}, {"Txt": "context = home"}]}},
+        {"Txt": "a", "Meta": {"Subs": [{"Txt": "estimate = 9"}]}},
+        {"Txt": "c", "Meta": {"Subs": [{"Txt": "estimate = 100"}, {"Txt": "context = Work"}]}}]}]}}`))
+    parent := m.Title.GetSubs()[0]
+    link := NewLink(parent.GetSubs()[0])
+    m.AddSubAfterThis(parent.GetSubs()[2], link)
+
+    m.OpenTable(parent)
+    m.table.row = 3
+    m.table.column = 2
+    m.EditTableCell()
+
+    if nil != m.prompt || "" == m.status {
+        t.Error("Expected the cells of links not to be edited")
+    }
+
+    m.SetTableCell("5")
+
+    if _, found := MetaValue(link, "estimate"); found {
+        t.Error("Expected no property on the link")
+    }
+}
@@ -0,0 +1,303 @@
+package goutlinelib
+
+import(
+    "fmt"
+    "sort"
+    "strings"
+    "unicode"
+
+    tea "github.com/charmbracelet/bubbletea"
+    "github.com/charmbracelet/lipgloss"
+)
+
+// meta property with the comma separated tags of an item
+const metaTags = "tags"
+
+// config key: if enabled, words like #tag in the text of items count as tags,
+// too
+const configInlineTags = "inlinetags"
+
+// ItemTags returns the stored tags of an item.
+func ItemTags(item OItem) []string {
+    value, _ := MetaValue(item, metaTags)
+
+    var result []string
+
+    for _, tag := range strings.Split(value, ",") {
+        if tag = strings.TrimSpace(tag); "" != tag {
+            result = append(result, tag)
+        }
+    }
+
+    return result
+}
+
+// unwrittenTags returns the stored tags of item that are not written at the
+// end of its text already.
+func unwrittenTags(item OItem) []string {
+    _, written := splitTrailingTags(item.GetTxt())
+
+    var result []string
+
+    for _, tag := range ItemTags(item) {
+        if !containsTag(written, tag) {
+            result = append(result, tag)
+        }
+    }
+
+    return result
+}
+
+// ParseTags parses tags separated by commas or spaces, optionally written as
+// #tag.
+func ParseTags(value string) []string {
+    var result []string
+
+    for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return ',' == r || unicode.IsSpace(r) }) {
+        if tag = strings.TrimLeft(tag, "#"); "" != tag && !containsTag(result, tag) {
+            result = append(result, tag)
+        }
+    }
+
+    return result
+}
+
+// SetItemTags stores the tags of an item; without tags, the property is
+// removed.
+func SetItemTags(item OItem, tags []string) {
+    if 0 == len(tags) {
+        DeleteMetaValue(item, metaTags)
+    } else {
+        SetMetaValue(item, metaTags, strings.Join(tags, ", "))
+    }
+}
+
+func containsTag(tags []string, tag string) bool {
+    for _, cur := range tags {
+        if strings.EqualFold(cur, tag) {
+            return true
+        }
+    }
+
+    return false
+}
+
+func isInlineTag(word string) bool {
+    if len(word) < 2 || '#' != word[0] {
+        return false
+    }
+
+    for i, r := range word[1:] {
+        if !(unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || strings.ContainsRune("-_/", r)))) {
+            return false
+        }
+    }
+
+    return true
+}
+
+// InlineTags returns the words of txt of the form #tag (starting with a
+// letter, so that e.g. issue numbers are not tags).
+func InlineTags(txt string) []string {
+    var result []string
+
+    for _, word := range strings.Fields(txt) {
+        if isInlineTag(word) && !containsTag(result, word[1:]) {
+            result = append(result, word[1:])
+        }
+    }
+
+    return result
+}
+
+// splitTrailingTags removes the inline tags at the end of txt, keeping at
+// least the first word.
+func splitTrailingTags(txt string) (string, []string) {
+    words := strings.Fields(txt)
+    end := len(words)
+
+    for end > 1 && isInlineTag(words[end - 1]) {
+        end--
+    }
+
+    if end == len(words) {
+        return txt, nil
+    }
+
+    var tags []string
+
+    for _, word := range words[end:] {
+        if !containsTag(tags, word[1:]) {
+            tags = append(tags, word[1:])
+        }
+    }
+
+    // keep the spacing within the remaining text
+    rest := txt
+
+    for i := len(words) - 1; i >= end; i-- {
+        rest = strings.TrimRightFunc(rest, unicode.IsSpace)
+        rest = rest[:len(rest) - len(words[i])]
+    }
+
+    return strings.TrimRightFunc(rest, unicode.IsSpace), tags
+}
+
+// Tags returns the stored tags of an item and, if enabled, its inline tags.
+func (m *model) Tags(item OItem) []string {
+    result := ItemTags(item)
+
+    if m.ConfigBool(configInlineTags, false) {
+        for _, tag := range InlineTags(item.GetTxt()) {
+            if !containsTag(result, tag) {
+                result = append(result, tag)
+            }
+        }
+    }
+
+    return result
+}
+
+// HasTag tells whether item has the given tag, ignoring case.
+func (m *model) HasTag(item OItem, tag string) bool {
+    return containsTag(m.Tags(item), tag)
+}
+
+// EditTags asks for the tags of item; the change can be undone.
+func (m *model) EditTags(item OItem) {
+    if !canHaveMeta(item) {
+        m.status = "links have no tags"
+        return
+    }
+
+    m.OpenPrompt("tags: ", strings.Join(ItemTags(item), ", "), func(m *model, value string) {
+        tags := ParseTags(value)
+
+        if strings.Join(tags, ", ") == strings.Join(ItemTags(item), ", ") {
+            return
+        }
+
+        m.PushUndo()
+        SetItemTags(item, tags)
+        item.SetTimestampChangedNow()
+        m.MarkDirty()
+        m.UpdateLinearizedMapping()
+    })
+}
+
+type TagCount struct {
+    Tag string
+    Count int
+}
+
+// TagCounts returns all tags in the outline with the number of items having
+// them, sorted by tag; tags differing only in case are counted together.
+func (m *model) TagCounts() []TagCount {
+    counts := make(map[string]*TagCount)
+
+    walkStored(m.Title, func(item OItem) {
+        // transclusions would count their target twice
+        if _, ok := item.(*oitemproxy); ok || item == m.Title {
+            return
+        }
+
+        for _, tag := range m.Tags(item) {
+            key := strings.ToLower(tag)
+
+            if nil == counts[key] {
+                counts[key] = &TagCount{Tag: tag}
+            }
+
+            counts[key].Count++
+        }
+    })
+
+    var result []TagCount
+
+    for _, count := range counts {
+        result = append(result, *count)
+    }
+
+    sort.Slice(result, func(i, j int) bool {
+        return strings.ToLower(result[i].Tag) < strings.ToLower(result[j].Tag)
+    })
+
+    return result
+}
+
+// a list of all tags to filter the outline by one of them
+type tagList struct {
+    tags []TagCount
+    cursor int
+}
+
+func (m *model) OpenTagList() {
+    tags := m.TagCounts()
+
+    if 0 == len(tags) {
+        m.status = "no tags"
+        return
+    }
+
+    m.tagList = &tagList{tags: tags}
+}
+
+// FilterByTag shows only the items with tag.
+func (m *model) FilterByTag(tag string) error {
+    return m.applyFilter(tagFilter{tag: tag}, "#" + tag)
+}
+
+func (m *model) handleTagListKey(msg tea.KeyMsg) {
+    list := m.tagList
+
+    switch msg.String() {
+
+    case "up", "k":
+        if list.cursor > 0 {
+            list.cursor--
+        }
+
+    case "down", "j":
+        if list.cursor < len(list.tags) - 1 {
+            list.cursor++
+        }
+
+    case "enter":
+        m.tagList = nil
+
+        if err := m.FilterByTag(list.tags[list.cursor].Tag); nil != err {
+            m.status = err.Error()
+        }
+
+    case "esc", "q", "ctrl+c":
+        m.tagList = nil
+    }
+}
+
+func (m model) tagListView() string {
+    list := m.tagList
+
+    s := headerStyle.Render("Tags") + "\n\n"
+
+    for i, tag := range list.tags {
+        line := fmt.Sprintf("#%s (%d)", tag.Tag, tag.Count)
+
+        s += listLine(line, i == list.cursor)
+    }
+
+    s += "\nenter: show only items with this tag   esc: cancel\n"
+
+    return s
+}
+
+// tagsView returns the stored tags of item as shown next to its text, leaving
+// out those at the end of the text.
+func tagsView(item OItem) string {
+    tags := unwrittenTags(item)
+
+    if 0 == len(tags) {
+        return ""
+    }
+
+    return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(" #" + strings.Join(tags, " #"))
+}
@@ -0,0 +1,153 @@
+package goutlinelib
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParseTags(t *testing.T) {
+    if tags := ParseTags("#work, home  urgent,Work"); strings.Join(tags, "|") != "work|home|urgent" {
+        t.Error("Unexpected tags", tags)
+    }
+
+    if tags := InlineTags("fix #12 for #work today #work #a-b"); strings.Join(tags, "|") != "work|a-b" {
+        t.Error("Unexpected inline tags", tags)
+    }
+
+    if txt, tags := splitTrailingTags("buy  milk #shop #errand"); txt != "buy  milk" || strings.Join(tags, "|") != "shop|errand" {
+        t.Error("Unexpected trailing tags", txt, tags)
+    }
+
+    if txt, tags := splitTrailingTags("#only"); txt != "#only" || nil != tags {
+        t.Error("Expected the first word to be kept, but got", txt, tags)
+    }
+
+    if txt, tags := splitOrgTags("Headline  :a:b_2:"); txt != "Headline" || strings.Join(tags, "|") != "a|b_2" {
+        t.Error("Unexpected org tags", txt, tags)
+    }
+
+    if txt, tags := splitOrgTags("time 10:30:"); txt != "time 10:30:" || nil != tags {
+        t.Error("Unexpected org tags", txt, tags)
+    }
+}
+
+func TestEditTagsIsUndoable(t *testing.T) {
+    m := InitialModel()
+    item := m.Title.GetSubs()[0]
+
+    m.EditTags(item)
+    m.prompt.onConfirm(&m, "#work, home")
+
+    if tags := ItemTags(item); strings.Join(tags, "|") != "work|home" || !m.IsDirty() {
+        t.Fatal("Expected tags to be set, but got", tags)
+    }
+
+    m.PopUndo()
+
+    if tags := ItemTags(m.Title.GetSubs()[0]); 0 != len(tags) {
+        t.Error("Expected tags to be removed by undo, but got", tags)
+    }
+}
+
+func TestTagCountsAndFilter(t *testing.T) {
+    m := InitialModel()
+    report := m.AddNewItem(m.Title.GetSubs()[0])
+    SetMetaValue(report, metaTags, "urgent, office")
+    home := m.AddNewItem(m.Title)
+    home.SetTxt("home #Urgent")
+
+    counts := m.TagCounts()
+
+    if len(counts) != 2 || counts[0] != (TagCount{Tag: "office", Count: 1}) || counts[1].Count != 1 {
+        t.Error("Expected inline tags to be ignored by default, but got", counts)
+    }
+
+    m.SetConfigValue(configInlineTags, "on")
+    counts = m.TagCounts()
+
+    if len(counts) != 2 || counts[1].Tag != "urgent" || counts[1].Count != 2 {
+        t.Error("Expected inline tags to be counted, but got", counts)
+    }
+
+    if err := m.FilterByTag("urgent"); err != nil {
+        t.Fatal("Unexpected error", err)
+    }
+
+    if len(m.linearized) != 3 || m.linearized[1] != report || m.linearized[2] != home || m.filterExpr != "#urgent" {
+        t.Error("Expected items with the tag and their ancestors, but got", m.linearized)
+    }
+}
+
+func TestOrgHeadlineTags(t *testing.T) {
+    doc := "#+TITLE: Tags\n* First :work:home:\n* Second\n"
+
+    var m model
+    m.UnmarshalOrg([]byte(doc))
+
+    first := m.Title.GetSubs()[0]
+
+    if tags := ItemTags(first); first.GetTxt() != "First" || strings.Join(tags, "|") != "work|home" {
+        t.Error("Expected headline tags to be read, but got", first.GetTxt(), tags)
+    }
+
+    if res, _ := m.MarshalOrg(); string(res) != doc {
+        t.Error("Expected", doc, "after round trip, but got", string(res))
+    }
+
+    SetMetaValue(m.Title.GetSubs()[1], metaTags, "two words")
+    res, _ := m.MarshalOrg()
+
+    if !strings.Contains(string(res), ":tags: two words") {
+        t.Error("Expected tags that are no org tags to be kept as property, but got", string(res))
+    }
+}
+
+func TestMarkdownTags(t *testing.T) {
+    doc := "# Tags\n\n- Hardware #diy\n- Fix #12 #urgent\n"
+
+    var m model
+    m.UnmarshalMarkdown([]byte(doc))
+
+    hardware := m.Title.GetSubs()[0]
+
+    if tags := ItemTags(hardware); hardware.GetTxt() != "Hardware #diy" || strings.Join(tags, "|") != "diy" {
+        t.Error("Expected text as written and tags in the properties, but got", hardware.GetTxt(), tags)
+    }
+
+    if res, _ := m.MarshalMarkdown(); string(res) != doc {
+        t.Error("Expected", doc, "after round trip, but got", string(res))
+    }
+
+    SetItemTags(hardware, []string{"diy", "weekend"})
+
+    if res, _ := m.MarshalMarkdown(); !strings.Contains(string(res), "- Hardware #diy #weekend\n") {
+        t.Error("Expected tags missing from the text to be appended, but got", string(res))
+    }
+}
+
+func TestTagsInTheTextAreNotRepeated(t *testing.T) {
+    m := InitialModel()
+    item := m.Title.GetSubs()[0]
+    item.SetTxt("buy milk #shop")
+    SetItemTags(item, []string{"shop", "errand"})
+
+    if tags := unwrittenTags(item); strings.Join(tags, "|") != "errand" {
+        t.Error("Expected only the tags missing from the text to be shown, but got", tags)
+    }
+
+    if res, _ := m.MarshalOrg(); !strings.Contains(string(res), "* buy milk #shop :errand:\n") {
+        t.Error("Expected only the tags missing from the text as headline tags, but got", string(res))
+    }
+}
+
+func TestLinksHaveNoTags(t *testing.T) {
+    m := InitialModel()
+    link := NewLink(m.Title.GetSubs()[0])
+    m.AddSubAfterThis(m.Title.GetSubs()[0], link)
+
+    m.EditTags(link)
+
+    if nil != m.prompt || "" == m.status {
+        t.Error("Expected tags of links not to be edited")
+    }
+}
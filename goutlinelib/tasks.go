@@ -0,0 +1,158 @@
+package goutlinelib
+
+import(
+    "fmt"
+
+    "github.com/charmbracelet/lipgloss"
+)
+
+// property that marks an item as a task and remembers its TODO keyword (named
+// after org-mode's special property), so that plain items stay plain
+const metaTodo = "TODO"
+
+// IsTask reports whether the item is a task, i.e. shown with a check box.
+func IsTask(item OItem) bool {
+    if !canBeTask(item) {
+        return false
+    }
+
+    _, found := MetaValue(item, metaTodo)
+    return found || item.IsChecked()
+}
+
+// canBeTask tells whether item can be a task; links only refer to their
+// target, and external (and command) items cannot be checked.
+func canBeTask(item OItem) bool {
+    _, isExternal := item.(*oitemexternal)
+    return canHaveMeta(item) && !isExternal
+}
+
+// notTaskStatus returns the status telling that item, which cannot be a task,
+// cannot be what (e.g. "checked").
+func notTaskStatus(item OItem, what string) string {
+    if !canHaveMeta(item) {
+        return "links cannot be " + what
+    }
+
+    return "external items cannot be " + what
+}
+
+// config key for how the progress of the task subs of an item is shown:
+// progressCount (e.g. 3/7), progressPercent or progressOff
+const configProgress = "progress"
+
+const (
+    progressCount = "count"
+    progressPercent = "percent"
+    progressOff = "off"
+)
+
+// config key: if enabled, items are checked as soon as all of their task subs
+// are, and unchecked again when one of them is unchecked
+const configAutoCheck = "autocheck"
+
+// TaskProgress returns the number of checked tasks among the subs of item, and
+// the number of tasks among them.
+func TaskProgress(item OItem) (done int, total int) {
+    for _, sub := range item.GetSubs() {
+        if !IsTask(sub) {
+            continue
+        }
+
+        total++
+
+        if sub.IsChecked() {
+            done++
+        }
+    }
+
+    return
+}
+
+// progressView returns the progress of the task subs of item as shown next to
+// its text.
+func (m *model) progressView(item OItem) string {
+    done, total := TaskProgress(item)
+
+    if 0 == total {
+        return ""
+    }
+
+    var progress string
+
+    switch value, _ := m.ConfigValue(configProgress); value {
+
+    case progressOff:
+        return ""
+
+    case progressPercent:
+        progress = fmt.Sprintf(" %d%%", 100 * done / total)
+
+    default:
+        progress = fmt.Sprintf(" %d/%d", done, total)
+    }
+
+    color := lipgloss.Color("245")
+
+    if done == total {
+        color = lipgloss.Color("34")
+    }
+
+    return lipgloss.NewStyle().Foreground(color).Render(progress)
+}
+
+// setChecked checks or unchecks item; checked items become tasks, so that
+// they keep their check box when unchecked again. Links cannot be checked.
+func setChecked(item OItem, checked bool) {
+    if !canBeTask(item) {
+        return
+    }
+
+    item.SetChecked(checked)
+
+    if _, found := MetaValue(item, metaTodo); checked && !found {
+        SetMetaValue(item, metaTodo, "TODO")
+    }
+}
+
+// autoCheckParents checks the ancestors of item whose task subs are all
+// checked now, and unchecks the ones whose task subs are not anymore.
+func (m *model) autoCheckParents(item OItem) {
+    if !m.ConfigBool(configAutoCheck, false) {
+        return
+    }
+
+    for parent := item.GetParent(); nil != parent && parent != m.Title; parent = parent.GetParent() {
+        done, total := TaskProgress(parent)
+        complete := total > 0 && done == total
+
+        if complete == parent.IsChecked() {
+            return
+        }
+
+        setChecked(parent, complete)
+    }
+}
+
+// ToggleTask makes item a task (shown with a check box) or a plain item again.
+func (m *model) ToggleTask(item OItem) {
+    if !canBeTask(item) {
+        m.status = notTaskStatus(item, "tasks")
+        return
+    }
+
+    m.PushUndo()
+
+    if IsTask(item) {
+        item.SetChecked(false)
+        DeleteMetaValue(item, metaTodo)
+    } else {
+        SetMetaValue(item, metaTodo, "TODO")
+    }
+
+    item.SetTimestampChangedNow()
+    m.autoCheckParents(item)
+    m.MarkDirty()
+
+    m.updateLinearizedKeepingCursor()
+}
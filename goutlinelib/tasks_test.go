@@ -0,0 +1,122 @@
+package goutlinelib
+
+import (
+    "testing"
+)
+
+func TestTaskFlag(t *testing.T) {
+    m := InitialModel()
+    item := m.Title.GetSubs()[0]
+
+    if IsTask(item) {
+        t.Fatal("Expected new items to be plain items")
+    }
+
+    m.ToggleTask(item)
+
+    if !IsTask(item) || item.IsChecked() || !m.IsDirty() {
+        t.Error("Expected item to be an unchecked task")
+    }
+
+    m.ToggleChecked(item)
+    m.ToggleTask(item)
+
+    if IsTask(item) || item.IsChecked() {
+        t.Error("Expected checked item to become a plain item again")
+    }
+
+    m.ToggleChecked(item)
+    m.ToggleChecked(item)
+
+    if !IsTask(item) {
+        t.Error("Expected item checked once to stay a task")
+    }
+
+    m.PopUndo()
+    m.PopUndo()
+    m.PopUndo()
+
+    if cur := m.Title.GetSubs()[0]; !IsTask(cur) || !cur.IsChecked() {
+        t.Error("Expected undo to restore the checked task")
+    }
+}
+
+func TestTaskProgressAndAutoCheck(t *testing.T) {
+    m := InitialModel()
+    parent := m.Title.GetSubs()[0]
+    first := m.AddNewItem(parent)
+    second := m.AddNewItem(parent)
+    m.AddNewItem(parent).SetTxt("plain")
+
+    m.ToggleTask(first)
+    m.ToggleTask(second)
+    m.ToggleChecked(first)
+
+    if done, total := TaskProgress(parent); done != 1 || total != 2 {
+        t.Error("Expected 1/2, but got", done, total)
+    }
+
+    m.ToggleChecked(second)
+
+    if parent.IsChecked() {
+        t.Error("Expected parent not to be checked without autocheck")
+    }
+
+    m.SetConfigValue(configAutoCheck, "on")
+    m.ToggleChecked(second)
+    m.ToggleChecked(second)
+
+    if !parent.IsChecked() || !IsTask(parent) {
+        t.Error("Expected parent to be checked when all task subs are")
+    }
+
+    m.ToggleChecked(first)
+
+    if parent.IsChecked() {
+        t.Error("Expected parent to be unchecked again")
+    }
+
+    m.PopUndo()
+
+    if !m.Title.GetSubs()[0].IsChecked() {
+        t.Error("Expected undo to restore the checked parent in the same step")
+    }
+}
+
+func TestLinksAreNoTasks(t *testing.T) {
+    m := InitialModel()
+    target := m.Title.GetSubs()[0]
+    link := NewLink(target)
+    m.AddSubAfterThis(target, link)
+
+    m.ToggleChecked(link)
+
+    if link.IsChecked() || IsTask(link) || "" == m.status {
+        t.Error("Expected links not to be checked")
+    }
+
+    m.status = ""
+    m.ToggleTask(link)
+
+    if IsTask(link) || "" == m.status {
+        t.Error("Expected links not to become tasks")
+    }
+}
+
+func TestExternalItemsAreNoTasks(t *testing.T) {
+    m := InitialModel()
+    command := NewCommand("echo hello")
+    m.AddSubAfterThis(m.Title.GetSubs()[0], command)
+
+    m.ToggleTask(command)
+
+    if IsTask(command) || "external items cannot be tasks" != m.status {
+        t.Error("Expected command items not to become tasks, but got", m.status)
+    }
+
+    m.ToggleChecked(command)
+
+    if IsTask(command) || "external items cannot be checked" != m.status {
+        t.Error("Expected command items not to be checked, but got", m.status)
+    }
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+    "errors"
+    "flag"
     "fmt"
+    "io/ioutil"
     "os"
 
     tea "github.com/charmbracelet/bubbletea"
@@ -13,19 +16,136 @@ import (
 )
 
 
+func usage() {
+    fmt.Printf("usage: goutline [--] [file]\n")
+    fmt.Printf("       goutline -html [-o <output.html>] <file>\n")
+    fmt.Printf("       goutline -check <file>...\n")
+}
+
+// options given on the command line; a file named like an option can be
+// given after "--"
+type options struct {
+    html bool
+    check bool
+
+    // name of the exported file, derived from the input file if empty
+    output string
+
+    filenames []string
+}
+
+func parseArgs(args []string) (options, error) {
+    var result options
+
+    flags := flag.NewFlagSet("goutline", flag.ContinueOnError)
+    flags.SetOutput(ioutil.Discard)
+    flags.BoolVar(&result.html, "html", false, "export as a self-contained HTML page")
+    flags.BoolVar(&result.check, "check", false, "validate files")
+    flags.StringVar(&result.output, "o", "", "name of the exported file")
+
+    if err := flags.Parse(args); nil != err {
+        return result, err
+    }
+
+    result.filenames = flags.Args()
+
+    switch {
+
+    case result.html && result.check:
+        return result, errors.New("-html and -check cannot be combined")
+
+    case "" != result.output && !result.html:
+        return result, errors.New("-o is only used with -html")
+
+    case result.html && 1 != len(result.filenames):
+        return result, errors.New("-html expects exactly one file")
+
+    case result.check && 0 == len(result.filenames):
+        return result, errors.New("-check expects at least one file")
+
+    case !result.html && !result.check && len(result.filenames) > 1:
+        return result, errors.New("only one file can be opened")
+    }
+
+    return result, nil
+}
+
+func check(filenames []string) int {
+    result := 0
+
+    for _, filename := range filenames {
+        _, err := goutlinelib.ModelFromFile(filename)
+
+        var loadErr *goutlinelib.LoadError
+
+        if errors.As(err, &loadErr) {
+            for _, problem := range loadErr.Problems {
+                fmt.Printf("%s: %s\n", filename, problem)
+            }
+
+            result = 1
+        } else if err != nil {
+            fmt.Printf("%s: %v\n", filename, err)
+            result = 1
+        }
+    }
+
+    return result
+}
+
+func exportHTML(filename string, htmlFilename string) int {
+    if "" == htmlFilename {
+        htmlFilename = goutlinelib.ExportFilename(filename, ".html")
+    }
+
+    if err := goutlinelib.ExportHTMLFile(filename, htmlFilename); err != nil {
+        fmt.Printf("Could not export %s: %v\n", filename, err)
+        return 1
+    }
+
+    return 0
+}
+
 func main() {
     var filename string
 
-    if len(os.Args) > 1 {
-        filename = os.Args[1]
+    opts, err := parseArgs(os.Args[1:])
+
+    if errors.Is(err, flag.ErrHelp) {
+        usage()
+        os.Exit(0)
+    } else if err != nil {
+        fmt.Printf("%v\n", err)
+        usage()
+        os.Exit(2)
+    }
+
+    if opts.html {
+        os.Exit(exportHTML(opts.filenames[0], opts.output))
+    }
+
+    if opts.check {
+        os.Exit(check(opts.filenames))
+    }
+
+    if len(opts.filenames) > 0 {
+        filename = opts.filenames[0]
     } else {
         filename = "out.json"
     }
 
     m, err := goutlinelib.ModelFromFile(filename)
 
-    if err != nil {
-        fmt.Printf("Could not load file: %s; using default contents (error was: %w)\n\n", filename, err)
+    var loadErr *goutlinelib.LoadError
+
+    if errors.As(err, &loadErr) && m.IsNewerVersion() {
+        fmt.Printf("%v\n\nOpening %s read-only; it can only be exported under a new name.\n\n", loadErr, filename)
+    } else if errors.As(err, &loadErr) {
+        // keep the damaged file as it is until saving is explicitly allowed
+        fmt.Printf("%v\n\nOpening %s read-only; press W to allow saving the repaired outline.\n\n", loadErr, filename)
+        m.SetReadOnly(true)
+    } else if err != nil {
+        fmt.Printf("Could not load file: %s; using default contents (error was: %v)\n\n", filename, err)
         m = goutlinelib.InitialModel()
         m.SetFilename(filename)
     }
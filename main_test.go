@@ -4,82 +4,34 @@ import (
     "testing"
 )
 
-func TestLevelNoParent(t *testing.T) {
-    o := oitem{Txt: "o"}
-    
-    res := o.Level(nil)
-    if res != 0 {
-        t.Error("Expected", 0, "for level of oitem, but was", res)
-    }
-}
-
-func TestLevelOneParent(t *testing.T) {
-    op := oitem{Txt: "op"}
-    o  := oitem{Txt: "o", parent: &op}
-
-    res := o.Level(nil)
-    if res != 1 {
-        t.Error("Expected", 1, "for level of oitem when checking up to nil, but was", res)
-    }
+func TestParseArgs(t *testing.T) {
+    opts, err := parseArgs([]string{"html"})
 
-    res = o.Level(&op)
-    if res != 1 {
-        t.Error("Expected", 1, "for level of oitem when checking up to item, but was", res)
+    if err != nil || opts.html || len(opts.filenames) != 1 || opts.filenames[0] != "html" {
+        t.Error("Expected a file named like an option to be opened, but got", opts, err)
     }
-}
 
-func TestLevelTwoParents(t *testing.T) {
-    opp := oitem{Txt: "opp"}
-    op  := oitem{Txt: "op", parent: &opp}
-    o   := oitem{Txt: "o",  parent: &op}
+    opts, err = parseArgs([]string{"-html", "-o", "out.html", "in.json"})
 
-    res := o.Level(nil)
-    if res != 2 {
-        t.Error("Expected", 2, "for level of oitem when checking up to nil, but was", res)
+    if err != nil || !opts.html || opts.output != "out.html" || opts.filenames[0] != "in.json" {
+        t.Error("Expected HTML export, but got", opts, err)
     }
 
-    res = o.Level(&opp)
-    if res != 2 {
-        t.Error("Expected", 2, "for level of oitem when checking up to nil, but was", res)
-    }
+    opts, err = parseArgs([]string{"-check", "a.json", "b.org"})
 
-    res = o.Level(&op)
-    if res != 1 {
-        t.Error("Expected", 1, "for level of oitem when checking up to item, but was", res)
+    if err != nil || !opts.check || len(opts.filenames) != 2 {
+        t.Error("Expected check of two files, but got", opts, err)
     }
-}
 
-func TestLinearizationSimple(t *testing.T) {
-    m := InitialModel()
-
-    if m.linearCount != 3 {
-        t.Error("Expected", 3, "for m.linearCount, but got", m.linearCount)
-    }
+    opts, err = parseArgs([]string{"--", "-check"})
 
-    if len(m.Title.Subs) != 3 {
-        t.Error("Expected", 3, "for m.Title.Subs, but got", len(m.Title.Subs))
+    if err != nil || opts.check || opts.filenames[0] != "-check" {
+        t.Error("Expected file after -- to be opened, but got", opts, err)
     }
 
-    if len(m.linearized) != 3 {
-        t.Error("Expected", 3, "for m.linearized, but got", len(m.linearized))
-    }
-
-    m.Expand(m.Title.Subs[1])
-
-    if m.linearCount != 3 {
-        t.Error("Expected", 3, "for m.linearCount, but got", m.linearCount)
-    }
-
-    if len(m.linearized) != 3 {
-        t.Error("Expected", 3, "for m.linearized, but got", len(m.linearized))
-    }
-}
-
-func TestSetTitle(t *testing.T) {
-    m := InitialModel()
-
-    m.SetTitle("foo")
-    if m.Title.Txt != "foo" {
-        t.Error("Expected", "foo", "for m.Title, but got", m.Title)
+    for _, args := range [][]string{{"-html"}, {"-check"}, {"-o", "x.html", "a.json"}, {"a.json", "b.json"}, {"-html", "-check", "a.json"}} {
+        if _, err := parseArgs(args); err == nil {
+            t.Error("Expected error for", args)
+        }
     }
 }